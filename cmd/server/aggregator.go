@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/config"
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/aggregator"
+)
+
+func newPolicy(cfg config.AggregatorConfig) aggregator.Policy {
+	switch cfg.Policy {
+	case config.AggregationPolicyBestEffort:
+		return aggregator.PolicyBestEffort
+	case config.AggregationPolicyQuorum:
+		return aggregator.PolicyQuorum(cfg.Quorum)
+	default:
+		return aggregator.PolicyStrict
+	}
+}
+
+func newMergeStrategy(cfg config.MergeConfig) aggregator.MergeStrategy {
+	switch cfg.Strategy {
+	case config.MergeStrategyDedup:
+		return aggregator.NewDedup(cfg.Priority...)
+	default:
+		return aggregator.Concat
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+
+	sdkhttp "github.com/linden-honey/linden-honey-sdk-go/transport/http"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/fetcher"
+)
+
+type breakersResponse struct {
+	Breakers map[string]fetcher.CircuitState `json:"breakers"`
+}
+
+// breakersHTTPHandler returns the handler reporting the state of the circuit breakers of the sources.
+// It's served next to the health endpoint, the application is up even if the circuits are open.
+func breakersHTTPHandler(breakers map[string]*fetcher.CircuitBreaker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := breakersResponse{
+			Breakers: make(map[string]fetcher.CircuitState, len(breakers)),
+		}
+		for id, cb := range breakers {
+			res.Breakers[id] = cb.State()
+		}
+
+		_ = sdkhttp.EncodeJSONResponse(w, http.StatusOK, res)
+	})
+}
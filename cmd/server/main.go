@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
 	"syscall"
 	"time"
 
@@ -20,10 +22,14 @@ import (
 	"github.com/linden-honey/linden-honey-scraper-go/pkg/config"
 	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
 	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/aggregator"
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/fetcher"
 	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/parser"
 )
 
 func main() {
+	configFile := flag.String("config", "", "path to a YAML or TOML config file, overrides the "+config.EnvConfigFile+" env var")
+	flag.Parse()
+
 	var (
 		ctx    context.Context
 		cancel context.CancelFunc
@@ -46,7 +52,12 @@ func main() {
 	var cfg *config.Config
 	{
 		var err error
-		cfg, err = config.New()
+		var opts []config.Option
+		if *configFile != "" {
+			opts = append(opts, config.WithFile(*configFile))
+		}
+
+		cfg, err = config.New(opts...)
 		if err != nil {
 			fatal(logger, fmt.Errorf("failed to initialize a config: %w", err))
 		}
@@ -54,23 +65,51 @@ func main() {
 
 	_ = logger.Log("msg", "initialize services")
 
+	breakers := make(map[string]*fetcher.CircuitBreaker)
+
 	var scrSvc scraper.Service
 	{
-		var err error
-		var grobScrSvc scraper.Service
-		{
-			grobScrSvc, err = newScraper(cfg.Scrapers.Grob, parser.NewGrobParser())
+		sources := make([]aggregator.Source, 0, len(cfg.Scrapers))
+		for _, scrCfg := range cfg.Scrapers {
+			p, err := parser.New(scrCfg.Parser)
+			if err != nil {
+				fatal(logger, fmt.Errorf("failed to initialize %s parser: %w", scrCfg.Name, err))
+			}
+
+			var cb *fetcher.CircuitBreaker
+			if scrCfg.CircuitBreaker.Enabled && scrCfg.Fixtures.Mode != config.FixturesModeReplay {
+				cb, err = newCircuitBreaker(
+					scrCfg.CircuitBreaker,
+					log.With(logger, "component", "circuit_breaker", "scraper_id", scrCfg.Name),
+				)
+				if err != nil {
+					fatal(logger, fmt.Errorf("failed to initialize %s circuit breaker: %w", scrCfg.Name, err))
+				}
+
+				breakers[scrCfg.Name] = cb
+			}
+
+			svc, err := newScraper(scrCfg, p, cb)
 			if err != nil {
-				fatal(logger, fmt.Errorf("failed to initialize grob scraper: %w", err))
+				fatal(logger, fmt.Errorf("failed to initialize %s scraper: %w", scrCfg.Name, err))
 			}
 
-			grobScrSvc = middleware.Compose(
-				scraper.LoggingMiddleware(log.With(logger, "component", "scraper", "scraper_id", "grob")),
-			)(grobScrSvc)
+			srcSvc := middleware.Compose(
+				scraper.LoggingMiddleware(log.With(logger, "component", "scraper", "scraper_id", scrCfg.Name)),
+			)(svc)
+
+			sources = append(sources, aggregator.Source{
+				Name:    scrCfg.Name,
+				Service: srcSvc,
+			})
 		}
 
+		var err error
 		scrSvc, err = aggregator.New(
-			grobScrSvc,
+			sources,
+			aggregator.WithTimeout(cfg.Aggregator.Timeout),
+			aggregator.WithPolicy(newPolicy(cfg.Aggregator)),
+			aggregator.WithMergeStrategy(newMergeStrategy(cfg.Aggregator.Merge)),
 		)
 		if err != nil {
 			fatal(logger, fmt.Errorf("failed to initialize an aggregator: %w", err))
@@ -90,6 +129,7 @@ func main() {
 
 		if cfg.Health.Enabled {
 			r.Handle(cfg.Health.Path, health.NewHTTPHandler(health.NewNopService()))
+			r.Handle(path.Join(cfg.Health.Path, "breakers"), breakersHTTPHandler(breakers))
 		}
 
 		specHandler, err := specHTTPHandler(cfg.Spec)
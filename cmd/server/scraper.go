@@ -2,40 +2,172 @@ package main
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
-	"time"
+	"path/filepath"
+	"strings"
 
-	"golang.org/x/text/encoding/charmap"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"golang.org/x/text/encoding/htmlindex"
 
 	"github.com/linden-honey/linden-honey-scraper-go/pkg/config"
 	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
 	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/fetcher"
-	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/parser"
 )
 
-func newScraper(cfg config.ScraperConfig, p scraper.Parser) (*scraper.Scraper, error) {
+func newScraper(cfg config.ScraperConfig, p scraper.Parser, cb *fetcher.CircuitBreaker) (*scraper.Scraper, error) {
+	var sf scraper.Fetcher
+	if cfg.Fixtures.Mode == config.FixturesModeReplay {
+		r, err := fetcher.NewReplayer(cfg.Fixtures.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize a fixtures replayer: %w", err)
+		}
+
+		sf = r
+	} else {
+		f, err := newFetcher(cfg, cb)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize a fetcher: %w", err)
+		}
+
+		sf = f
+		if cfg.Fixtures.Mode == config.FixturesModeRecord {
+			sf, err = fetcher.NewRecorder(f, cfg.Fixtures.Dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize a fixtures recorder: %w", err)
+			}
+		}
+	}
+
+	return scraper.New(
+		sf,
+		p,
+		scraper.WithValidation(true),
+		scraper.WithPartialResults(cfg.PartialResults),
+	)
+}
+
+// newFetcher returns a network fetcher of the scraper, it's not used in the replay mode of the fixtures.
+func newFetcher(cfg config.ScraperConfig, cb *fetcher.CircuitBreaker) (*fetcher.Fetcher, error) {
 	u, err := url.Parse(cfg.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse scraper base url: %w", err)
 	}
 
-	f, err := fetcher.New(
-		u,
-		charmap.Windows1251,
-		fetcher.WithRetry(&fetcher.RetryConfig{
-			Attempts:    5,
-			MinInterval: 2 * time.Second,
-			MaxInterval: 10 * time.Second,
-			Factor:      2 * time.Second,
-		}),
-	)
+	client, err := newHTTPClient(cfg.Client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize a fetcher: %w", err)
+		return nil, fmt.Errorf("failed to initialize an http client: %w", err)
 	}
 
-	return scraper.New(
-		f,
-		parser.NewGrobParser(),
-		scraper.WithValidation(true),
+	enc, err := htmlindex.Get(cfg.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scraper encoding: %w", err)
+	}
+
+	fopts := []fetcher.Option{
+		fetcher.WithClient(client),
+		fetcher.WithHeader(parseHeader(cfg.Client.Headers)),
+		fetcher.WithMaxBodySize(cfg.MaxBodySize),
+		fetcher.WithAllowedContentTypes(cfg.ContentTypes...),
+		fetcher.WithRobots(cfg.Robots.Enabled),
+	}
+
+	if cfg.Retry.Enabled {
+		fopts = append(fopts, fetcher.WithRetry(&fetcher.RetryConfig{
+			Attempts:          cfg.Retry.Attempts,
+			Strategy:          fetcher.BackoffStrategy(cfg.Retry.Strategy),
+			MinInterval:       cfg.Retry.MinInterval,
+			MaxInterval:       cfg.Retry.MaxInterval,
+			Factor:            cfg.Retry.Factor,
+			MaxJitterInterval: cfg.Retry.MaxJitterInterval,
+		}))
+	}
+
+	if cfg.RateLimit.Enabled {
+		fopts = append(fopts, fetcher.WithRateLimit(&fetcher.RateLimitConfig{
+			RPS:   cfg.RateLimit.RPS,
+			Burst: cfg.RateLimit.Burst,
+		}))
+	}
+
+	if cfg.Client.UserAgent != "" {
+		fopts = append(fopts, fetcher.WithUserAgent(cfg.Client.UserAgent))
+	}
+
+	if cb != nil {
+		fopts = append(fopts, fetcher.WithCircuitBreaker(cb))
+	}
+
+	if cfg.Cache.Enabled {
+		c, err := newCache(cfg.Name, cfg.Cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize a cache: %w", err)
+		}
+
+		fopts = append(fopts, fetcher.WithCache(c, cfg.Cache.TTL))
+	}
+
+	return fetcher.New(
+		u,
+		enc,
+		fopts...,
 	)
 }
+
+func newHTTPClient(cfg config.ClientConfig) (*http.Client, error) {
+	var proxyURL *url.URL
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy url: %w", err)
+		}
+
+		proxyURL = u
+	}
+
+	return fetcher.NewClient(fetcher.ClientConfig{
+		Timeout:             cfg.Timeout,
+		ProxyURL:            proxyURL,
+		CAFile:              cfg.CAFile,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout,
+	})
+}
+
+// parseHeader parses headers in the "Name: value" format.
+func parseHeader(headers []string) http.Header {
+	h := make(http.Header, len(headers))
+	for _, v := range headers {
+		name, value, _ := strings.Cut(v, ":")
+		h.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	return h
+}
+
+// newCache returns a cache of the scraper.
+//
+// hint: each scraper gets its own subdirectory because a file cache evicts every entry of its directory
+func newCache(name string, cfg config.CacheConfig) (fetcher.Cache, error) {
+	switch cfg.Type {
+	case config.CacheTypeFile:
+		return fetcher.NewFileCache(filepath.Join(cfg.Dir, name), cfg.MaxSize)
+	case config.CacheTypeMemory:
+		return fetcher.NewMemoryCache(cfg.MaxSize), nil
+	default:
+		return nil, fmt.Errorf("unsupported cache type %q", cfg.Type)
+	}
+}
+
+func newCircuitBreaker(cfg config.CircuitBreakerConfig, logger log.Logger) (*fetcher.CircuitBreaker, error) {
+	return fetcher.NewCircuitBreaker(fetcher.CircuitBreakerConfig{
+		Threshold: cfg.Threshold,
+		Cooldown:  cfg.Cooldown,
+		OnStateChange: func(from, to fetcher.CircuitState) {
+			_ = level.Warn(logger).Log("msg", "circuit breaker state changed", "from", from, "to", to)
+		},
+	})
+}
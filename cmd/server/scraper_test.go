@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/config"
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/fetcher"
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/parser"
+)
+
+func Test_newCache(t *testing.T) {
+	cfg := config.DefaultScraperConfig.Cache
+	cfg.Dir = t.TempDir()
+	cfg.MaxSize = 1024
+
+	a, err := newCache("a", cfg)
+	if err != nil {
+		t.Fatalf("newCache() error = %v", err)
+	}
+	b, err := newCache("b", cfg)
+	if err != nil {
+		t.Fatalf("newCache() error = %v", err)
+	}
+
+	body := make([]byte, 400)
+	if err := a.Set("a", &fetcher.CacheEntry{URL: "a", Body: body}); err != nil {
+		t.Fatalf("Cache.Set() error = %v", err)
+	}
+	for _, key := range []string{"b1", "b2", "b3"} {
+		if err := b.Set(key, &fetcher.CacheEntry{URL: key, Body: body}); err != nil {
+			t.Fatalf("Cache.Set() error = %v", err)
+		}
+	}
+
+	if _, ok := a.Get("a"); !ok {
+		t.Errorf("Cache.Get() entry of another scraper ok = %v, want %v", ok, true)
+	}
+}
+
+func Test_newScraper_Replay(t *testing.T) {
+	cfg := config.DefaultScraperConfig
+	cfg.Name = "grob"
+	cfg.Parser = "grob"
+	cfg.Cache.Dir = filepath.Join(t.TempDir(), "cache")
+	cfg.Fixtures = config.FixturesConfig{
+		Mode: config.FixturesModeReplay,
+		Dir:  t.TempDir(),
+	}
+
+	if _, err := newScraper(cfg, parser.NewGrobParser(), nil); err != nil {
+		t.Fatalf("newScraper() error = %v", err)
+	}
+
+	if _, err := os.Stat(cfg.Cache.Dir); !os.IsNotExist(err) {
+		t.Errorf("newScraper() created the cache directory in the replay mode, stat error = %v", err)
+	}
+}
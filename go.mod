@@ -5,17 +5,20 @@ go 1.19
 // +heroku goVersion go1.19
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/PuerkitoBio/goquery v1.8.0
 	github.com/caarlos0/env/v6 v6.10.1
 	github.com/go-chi/chi/v5 v5.0.8
 	github.com/go-kit/log v0.2.1
 	github.com/linden-honey/linden-honey-api-go v0.0.6
 	github.com/linden-honey/linden-honey-sdk-go v0.1.1
+	golang.org/x/net v0.4.0
 	golang.org/x/text v0.5.0
+	golang.org/x/time v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/andybalholm/cascadia v1.3.1 // indirect
 	github.com/go-logfmt/logfmt v0.5.1 // indirect
-	golang.org/x/net v0.4.0 // indirect
 )
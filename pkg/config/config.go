@@ -2,49 +2,219 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/caarlos0/env/v6"
 )
 
 // Config is a configuration object.
 type Config struct {
-	Server   ServerConfig
-	Health   HealthConfig
-	Spec     SpecConfig
-	Scrapers ScrapersConfig
+	Server     ServerConfig     `yaml:"server"`
+	Health     HealthConfig     `yaml:"health"`
+	Spec       SpecConfig       `yaml:"spec"`
+	Aggregator AggregatorConfig `yaml:"aggregator"`
+	Scrapers   ScrapersConfig   `yaml:"scrapers"`
 }
 
 // ServerConfig is a configuration object.
 type ServerConfig struct {
-	Host string `env:"SERVER_HOST"`
-	Port int    `env:"SERVER_PORT"`
+	Host string `yaml:"host" env:"SERVER_HOST"`
+	Port int    `yaml:"port" env:"SERVER_PORT"`
 }
 
 // HealthConfig is a configuration object.
 type HealthConfig struct {
-	Enabled bool   `env:"HEALTH_ENABLED"`
-	Path    string `env:"HEALTH_PATH"`
+	Enabled bool   `yaml:"enabled" env:"HEALTH_ENABLED"`
+	Path    string `yaml:"path" env:"HEALTH_PATH"`
 }
 
 // SpecConfig is a configuration object.
 type SpecConfig struct {
-	FilePath string `env:"SPEC_FILE_PATH"`
+	FilePath string `yaml:"file_path" env:"SPEC_FILE_PATH"`
 }
 
-// ScrapersConfig is a configuration object.
-type ScrapersConfig struct {
-	Grob ScraperConfig `envPrefix:"GROB_"`
+// AggregatorConfig is a configuration object.
+type AggregatorConfig struct {
+	Timeout time.Duration `yaml:"timeout" env:"AGGREGATOR_TIMEOUT"`
+	Policy  string        `yaml:"policy" env:"AGGREGATOR_POLICY"`
+	// Quorum is the minimum number of scrapers required to succeed with the quorum policy.
+	Quorum int         `yaml:"quorum" env:"AGGREGATOR_QUORUM"`
+	Merge  MergeConfig `yaml:"merge" envPrefix:"AGGREGATOR_MERGE_"`
 }
 
+// MergeConfig is a configuration object.
+type MergeConfig struct {
+	Strategy string `yaml:"strategy" env:"STRATEGY"`
+	// Priority is a list of scraper names, the lyrics of the first listed scraper are preferred.
+	Priority []string `yaml:"priority" env:"PRIORITY" envSeparator:","`
+}
+
+// ScrapersConfig is a list of scraper configurations.
+type ScrapersConfig []ScraperConfig
+
 // ScraperConfig is a configuration object.
+//
+// Env vars of a scraper are prefixed with its name, e.g. GROB_SCRAPER_BASE_URL for the "grob" scraper.
 type ScraperConfig struct {
-	BaseURL string `env:"SCRAPER_BASE_URL"`
+	Name     string `yaml:"name"`
+	BaseURL  string `yaml:"base_url" env:"SCRAPER_BASE_URL"`
+	Parser   string `yaml:"parser" env:"SCRAPER_PARSER"`
+	Encoding string `yaml:"encoding" env:"SCRAPER_ENCODING"`
+	// PartialResults enables returning the scraped songs along with the failures instead of failing on a broken page.
+	PartialResults bool `yaml:"partial_results" env:"SCRAPER_PARTIAL_RESULTS"`
+	// MaxBodySize is the max size of a response body in bytes, zero means no limit.
+	MaxBodySize int64 `yaml:"max_body_size" env:"SCRAPER_MAX_BODY_SIZE"`
+	// ContentTypes is a list of the allowed media types of a response, an empty list allows all types.
+	ContentTypes   []string             `yaml:"content_types" env:"SCRAPER_CONTENT_TYPES" envSeparator:","`
+	Retry          RetryConfig          `yaml:"retry" envPrefix:"SCRAPER_RETRY_"`
+	Cache          CacheConfig          `yaml:"cache" envPrefix:"SCRAPER_CACHE_"`
+	RateLimit      RateLimitConfig      `yaml:"rate_limit" envPrefix:"SCRAPER_RATE_LIMIT_"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker" envPrefix:"SCRAPER_CIRCUIT_BREAKER_"`
+	Robots         RobotsConfig         `yaml:"robots" envPrefix:"SCRAPER_ROBOTS_"`
+	Fixtures       FixturesConfig       `yaml:"fixtures" envPrefix:"SCRAPER_FIXTURES_"`
+	Client         ClientConfig         `yaml:"client" envPrefix:"SCRAPER_CLIENT_"`
+}
+
+// RetryConfig is a configuration object.
+type RetryConfig struct {
+	Enabled           bool          `yaml:"enabled" env:"ENABLED"`
+	Attempts          int           `yaml:"attempts" env:"ATTEMPTS"`
+	Strategy          string        `yaml:"strategy" env:"STRATEGY"`
+	MinInterval       time.Duration `yaml:"min_interval" env:"MIN_INTERVAL"`
+	MaxInterval       time.Duration `yaml:"max_interval" env:"MAX_INTERVAL"`
+	Factor            time.Duration `yaml:"factor" env:"FACTOR"`
+	MaxJitterInterval time.Duration `yaml:"max_jitter_interval" env:"MAX_JITTER_INTERVAL"`
+}
+
+// CacheConfig is a configuration object.
+type CacheConfig struct {
+	Enabled bool          `yaml:"enabled" env:"ENABLED"`
+	Type    string        `yaml:"type" env:"TYPE"`
+	Dir     string        `yaml:"dir" env:"DIR"`
+	TTL     time.Duration `yaml:"ttl" env:"TTL"`
+	MaxSize int64         `yaml:"max_size" env:"MAX_SIZE"`
+}
+
+// RateLimitConfig is a configuration object.
+type RateLimitConfig struct {
+	Enabled bool    `yaml:"enabled" env:"ENABLED"`
+	RPS     float64 `yaml:"rps" env:"RPS"`
+	Burst   int     `yaml:"burst" env:"BURST"`
+}
+
+// CircuitBreakerConfig is a configuration object.
+type CircuitBreakerConfig struct {
+	Enabled   bool          `yaml:"enabled" env:"ENABLED"`
+	Threshold int           `yaml:"threshold" env:"THRESHOLD"`
+	Cooldown  time.Duration `yaml:"cooldown" env:"COOLDOWN"`
+}
+
+// RobotsConfig is a configuration object.
+type RobotsConfig struct {
+	Enabled bool `yaml:"enabled" env:"ENABLED"`
+}
+
+// ClientConfig is a configuration object.
+type ClientConfig struct {
+	// Timeout is the time limit of a single request, zero means no timeout.
+	Timeout   time.Duration `yaml:"timeout" env:"TIMEOUT"`
+	UserAgent string        `yaml:"user_agent" env:"USER_AGENT"`
+	// Headers is a list of additional headers in the "Name: value" format.
+	Headers             []string      `yaml:"headers" env:"HEADERS" envSeparator:"|"`
+	ProxyURL            string        `yaml:"proxy_url" env:"PROXY_URL"`
+	CAFile              string        `yaml:"ca_file" env:"CA_FILE"`
+	MaxIdleConns        int           `yaml:"max_idle_conns" env:"MAX_IDLE_CONNS"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host" env:"MAX_IDLE_CONNS_PER_HOST"`
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host" env:"MAX_CONNS_PER_HOST"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout" env:"IDLE_CONN_TIMEOUT"`
+}
+
+// FixturesConfig is a configuration object.
+type FixturesConfig struct {
+	Mode string `yaml:"mode" env:"MODE"`
+	Dir  string `yaml:"dir" env:"DIR"`
+}
+
+const (
+	// RetryStrategyConstant is the strategy of constant delays between retries.
+	RetryStrategyConstant = "constant"
+	// RetryStrategyLinear is the strategy of linearly growing delays between retries.
+	RetryStrategyLinear = "linear"
+	// RetryStrategyExponential is the strategy of exponentially growing delays with full jitter between retries.
+	RetryStrategyExponential = "exponential"
+	// RetryStrategyDecorrelatedJitter is the strategy of decorrelated jitter delays between retries.
+	RetryStrategyDecorrelatedJitter = "decorrelated_jitter"
+)
+
+const (
+	// AggregationPolicyStrict is the policy requiring all scrapers to succeed.
+	AggregationPolicyStrict = "strict"
+	// AggregationPolicyBestEffort is the policy requiring at least one scraper to succeed.
+	AggregationPolicyBestEffort = "best_effort"
+	// AggregationPolicyQuorum is the policy requiring at least the quorum of scrapers to succeed.
+	AggregationPolicyQuorum = "quorum"
+)
+
+const (
+	// MergeStrategyConcat is the strategy of concatenating songs of all scrapers as is.
+	MergeStrategyConcat = "concat"
+	// MergeStrategyDedup is the strategy of merging the same song scraped by different scrapers.
+	MergeStrategyDedup = "dedup"
+)
+
+const (
+	// FixturesModeRecord is the mode of recording fetched responses to fixtures.
+	FixturesModeRecord = "record"
+	// FixturesModeReplay is the mode of serving responses from fixtures without network.
+	FixturesModeReplay = "replay"
+)
+
+const (
+	// CacheTypeFile is the type of a filesystem cache.
+	CacheTypeFile = "file"
+	// CacheTypeMemory is the type of an in-memory cache.
+	CacheTypeMemory = "memory"
+)
+
+// EnvConfigFile is the env var with the path of the config file used when [WithFile] is not passed.
+const EnvConfigFile = "CONFIG_FILE"
+
+// Option set an optional parameter for the config loading.
+type Option func(*options)
+
+type options struct {
+	filePath string
+}
+
+// WithFile sets the path of the YAML or TOML config file, values of env vars take precedence over the file.
+func WithFile(path string) Option {
+	return func(o *options) {
+		o.filePath = path
+	}
 }
 
 // New returns a pointer to the new instance of [Config] or an error.
-func New() (*Config, error) {
+func New(opts ...Option) (*Config, error) {
+	o := options{
+		filePath: os.Getenv(EnvConfigFile),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	cfg := DefaultConfig
-	if err := env.Parse(&cfg); err != nil {
+	cfg.Scrapers = append(ScrapersConfig(nil), DefaultConfig.Scrapers...)
+
+	if o.filePath != "" {
+		if err := readFile(o.filePath, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to read a config file %s: %w", o.filePath, err)
+		}
+	}
+
+	if err := parseEnv(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse env: %w", err)
 	}
 
@@ -54,3 +224,30 @@ func New() (*Config, error) {
 
 	return &cfg, nil
 }
+
+// parseEnv overrides the cfg with env vars, the vars of each scraper are prefixed with its name.
+func parseEnv(cfg *Config) error {
+	if err := env.Parse(cfg); err != nil {
+		return err
+	}
+
+	for i := range cfg.Scrapers {
+		s := &cfg.Scrapers[i]
+		if err := env.Parse(s, env.Options{Prefix: envPrefix(s.Name)}); err != nil {
+			return fmt.Errorf("failed to parse env of the %q scraper: %w", s.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// envPrefix returns the env vars prefix of the scraper name, e.g. "GROB_" for "grob".
+func envPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name) + "_"
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	fileConfig := func() *Config {
+		grob := DefaultScraperConfig
+		grob.Name = "grob"
+		grob.BaseURL = "https://www.gr-oborona.ru/"
+		grob.Parser = "grob"
+		grob.Retry.Attempts = 3
+		grob.Client.Timeout = 10 * time.Second
+		grob.Client.Headers = []string{"Accept-Language: ru"}
+
+		mirror := DefaultScraperConfig
+		mirror.Name = "mirror-1"
+		mirror.BaseURL = "https://mirror.example.com/"
+		mirror.Parser = "grob"
+		mirror.Encoding = "utf-8"
+		mirror.PartialResults = true
+		mirror.MaxBodySize = 20 << 20
+		mirror.ContentTypes = []string{"text/html", "application/xhtml+xml"}
+		mirror.Cache.Type = CacheTypeMemory
+		mirror.Robots.Enabled = false
+
+		cfg := DefaultConfig
+		cfg.Server = ServerConfig{
+			Host: "0.0.0.0",
+			Port: 9090,
+		}
+		cfg.Health.Path = "/healthz"
+		cfg.Aggregator.Timeout = time.Minute
+		cfg.Aggregator.Policy = AggregationPolicyQuorum
+		cfg.Aggregator.Quorum = 1
+		cfg.Aggregator.Merge.Priority = []string{"mirror-1"}
+		cfg.Scrapers = ScrapersConfig{grob, mirror}
+
+		return &cfg
+	}
+
+	type fields struct {
+		opts []Option
+		env  map[string]string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    func() *Config
+		wantErr string
+	}{
+		{
+			name: "ok  defaults",
+			want: func() *Config {
+				cfg := DefaultConfig
+				return &cfg
+			},
+		},
+		{
+			name: "ok  yaml",
+			fields: fields{
+				opts: []Option{WithFile("testdata/config.yaml")},
+			},
+			want: fileConfig,
+		},
+		{
+			name: "ok  toml",
+			fields: fields{
+				opts: []Option{WithFile("testdata/config.toml")},
+			},
+			want: fileConfig,
+		},
+		{
+			name: "ok  file from env",
+			fields: fields{
+				env: map[string]string{
+					EnvConfigFile: "testdata/config.yaml",
+				},
+			},
+			want: fileConfig,
+		},
+		{
+			name: "ok  env overrides file",
+			fields: fields{
+				opts: []Option{WithFile("testdata/config.yaml")},
+				env: map[string]string{
+					"SERVER_PORT":                  "8081",
+					"GROB_SCRAPER_CLIENT_TIMEOUT":  "20s",
+					"MIRROR_1_SCRAPER_BASE_URL":    "https://mirror.example.org/",
+					"GROB_SCRAPER_PARTIAL_RESULTS": "true",
+					"GROB_SCRAPER_ROBOTS_ENABLED":  "false",
+				},
+			},
+			want: func() *Config {
+				cfg := fileConfig()
+				cfg.Server.Port = 8081
+				cfg.Scrapers[0].Client.Timeout = 20 * time.Second
+				cfg.Scrapers[0].PartialResults = true
+				cfg.Scrapers[0].Robots.Enabled = false
+				cfg.Scrapers[1].BaseURL = "https://mirror.example.org/"
+
+				return cfg
+			},
+		},
+		{
+			name: "err  unknown key",
+			fields: fields{
+				opts: []Option{WithFile("testdata/unknown_key.yaml")},
+			},
+			wantErr: "scrapers[0].client.timeot",
+		},
+		{
+			name: "err  invalid value",
+			fields: fields{
+				opts: []Option{WithFile("testdata/invalid_value.yaml")},
+			},
+			wantErr: "scrapers[0]: invalid value rate_limit: invalid value rps",
+		},
+		{
+			name: "err  unsupported extension",
+			fields: fields{
+				opts: []Option{WithFile("testdata/config.json")},
+			},
+			wantErr: "unsupported config file extension",
+		},
+		{
+			name: "err  missing file",
+			fields: fields{
+				opts: []Option{WithFile("testdata/missing.yaml")},
+			},
+			wantErr: "testdata/missing.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvConfigFile, "")
+			for k, v := range tt.fields.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := New(tt.fields.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if want := tt.want(); !reflect.DeepEqual(got, want) {
+				t.Errorf("New() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
 var (
 	DefaultConfig = Config{
 		Server: ServerConfig{
@@ -13,10 +19,80 @@ var (
 		Spec: SpecConfig{
 			FilePath: "./api/openapi.json",
 		},
+		Aggregator: AggregatorConfig{
+			Timeout: 5 * time.Minute,
+			Policy:  AggregationPolicyStrict,
+			Merge: MergeConfig{
+				Strategy: MergeStrategyDedup,
+			},
+		},
 		Scrapers: ScrapersConfig{
-			Grob: ScraperConfig{
-				BaseURL: "https://www.gr-oborona.ru/",
+			{
+				Name:           "grob",
+				BaseURL:        "https://www.gr-oborona.ru/",
+				Parser:         "grob",
+				Encoding:       DefaultScraperConfig.Encoding,
+				MaxBodySize:    DefaultScraperConfig.MaxBodySize,
+				ContentTypes:   DefaultScraperConfig.ContentTypes,
+				Retry:          DefaultScraperConfig.Retry,
+				Cache:          DefaultScraperConfig.Cache,
+				RateLimit:      DefaultScraperConfig.RateLimit,
+				CircuitBreaker: DefaultScraperConfig.CircuitBreaker,
+				Robots:         DefaultScraperConfig.Robots,
+				Client:         DefaultScraperConfig.Client,
 			},
 		},
 	}
+
+	DefaultScraperConfig = ScraperConfig{
+		Encoding:       "windows-1251",
+		MaxBodySize:    10 << 20, // 10 MiB
+		ContentTypes:   []string{"text/html"},
+		Retry:          DefaultRetryConfig,
+		Cache:          DefaultCacheConfig,
+		RateLimit:      DefaultRateLimitConfig,
+		CircuitBreaker: DefaultCircuitBreakerConfig,
+		Robots:         DefaultRobotsConfig,
+		Client:         DefaultClientConfig,
+	}
+
+	DefaultRetryConfig = RetryConfig{
+		Enabled:     true,
+		Attempts:    5,
+		Strategy:    RetryStrategyExponential,
+		MinInterval: 2 * time.Second,
+		MaxInterval: 10 * time.Second,
+		Factor:      2 * time.Second,
+	}
+
+	DefaultCacheConfig = CacheConfig{
+		Enabled: true,
+		Type:    CacheTypeFile,
+		Dir:     filepath.Join(os.TempDir(), "linden-honey-scraper"),
+		TTL:     time.Hour,
+		MaxSize: 64 << 20, // 64 MiB
+	}
+
+	DefaultRateLimitConfig = RateLimitConfig{
+		Enabled: true,
+		RPS:     5,
+		Burst:   10,
+	}
+
+	DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+		Enabled:   true,
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+	}
+
+	DefaultRobotsConfig = RobotsConfig{
+		Enabled: true,
+	}
+
+	DefaultClientConfig = ClientConfig{
+		Timeout:             30 * time.Second,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
 )
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	sdkerrors "github.com/linden-honey/linden-honey-sdk-go/errors"
+)
+
+// ErrUnknownKey is an error returned when a config file contains a key not declared by [Config].
+var ErrUnknownKey = errors.New("unknown key")
+
+// UnmarshalYAML implements [yaml.Unmarshaler], unset values of a scraper are taken from [DefaultScraperConfig].
+func (cfg *ScraperConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain ScraperConfig // hint: prevents the recursion
+
+	v := plain(DefaultScraperConfig)
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+
+	*cfg = ScraperConfig(v)
+
+	return nil
+}
+
+// readFile reads the YAML or TOML config file on top of the cfg.
+func readFile(path string, cfg *Config) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml", ".toml":
+	default:
+		return fmt.Errorf("unsupported config file extension %q", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]any
+	switch ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+
+		// hint: TOML is decoded via YAML to share the defaults of list items
+		if data, err = yaml.Marshal(raw); err != nil {
+			return err
+		}
+	}
+
+	if err := checkKeys("", raw, reflect.TypeOf(cfg).Elem()); err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(data, cfg)
+}
+
+// checkKeys returns an error pointing to the first key of v not declared by the yaml tags of t.
+func checkKeys(path string, v any, t reflect.Type) error {
+	rv := reflect.ValueOf(v)
+	switch {
+	case t.Kind() == reflect.Struct && rv.Kind() == reflect.Map:
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			key := k
+			if path != "" {
+				key = path + "." + k
+			}
+
+			ft, ok := fields[k]
+			if !ok {
+				return sdkerrors.NewInvalidValueError(key, ErrUnknownKey)
+			}
+
+			if err := checkKeys(key, rv.MapIndex(reflect.ValueOf(k)).Interface(), ft); err != nil {
+				return err
+			}
+		}
+	case t.Kind() == reflect.Slice && rv.Kind() == reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			if err := checkKeys(fmt.Sprintf("%s[%d]", path, i), rv.Index(i).Interface(), t.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,27 +1,61 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
 	"strings"
 
+	"golang.org/x/text/encoding/htmlindex"
+
 	sdkerrors "github.com/linden-honey/linden-honey-sdk-go/errors"
 )
 
 // Validate validates a [Config] and returns an error if validation is failed.
 func (cfg Config) Validate() error {
 	if err := cfg.Server.Validate(); err != nil {
-		return sdkerrors.NewInvalidValueError("Server", err)
+		return sdkerrors.NewInvalidValueError("server", err)
 	}
 
 	if err := cfg.Health.Validate(); err != nil {
-		return sdkerrors.NewInvalidValueError("Health", err)
+		return sdkerrors.NewInvalidValueError("health", err)
 	}
 
 	if err := cfg.Spec.Validate(); err != nil {
-		return sdkerrors.NewInvalidValueError("Spec", err)
+		return sdkerrors.NewInvalidValueError("spec", err)
+	}
+
+	if err := cfg.Aggregator.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("aggregator", err)
 	}
 
 	if err := cfg.Scrapers.Validate(); err != nil {
-		return sdkerrors.NewInvalidValueError("Scrapers", err)
+		return err
+	}
+
+	if cfg.Aggregator.Policy == AggregationPolicyQuorum && cfg.Aggregator.Quorum > len(cfg.Scrapers) {
+		return sdkerrors.NewInvalidValueError(
+			"aggregator",
+			sdkerrors.NewInvalidValueError("quorum", errors.New("should be less than or equal to the number of scrapers")),
+		)
+	}
+
+	names := make(map[string]struct{}, len(cfg.Scrapers))
+	for _, s := range cfg.Scrapers {
+		names[s.Name] = struct{}{}
+	}
+
+	for i, name := range cfg.Aggregator.Merge.Priority {
+		if _, ok := names[name]; !ok {
+			return sdkerrors.NewInvalidValueError(
+				"aggregator",
+				sdkerrors.NewInvalidValueError(
+					"merge",
+					sdkerrors.NewInvalidValueError(fmt.Sprintf("priority[%d]", i), fmt.Errorf("unknown scraper %q", name)),
+				),
+			)
+		}
 	}
 
 	return nil
@@ -30,11 +64,11 @@ func (cfg Config) Validate() error {
 // Validate validates a [ServerConfig] and returns an error if validation is failed.
 func (cfg ServerConfig) Validate() error {
 	if strings.TrimSpace(cfg.Host) == "" {
-		return sdkerrors.NewInvalidValueError("Host", sdkerrors.ErrEmptyValue)
+		return sdkerrors.NewInvalidValueError("host", sdkerrors.ErrEmptyValue)
 	}
 
 	if cfg.Port <= 0 {
-		return sdkerrors.NewInvalidValueError("Port", sdkerrors.ErrNonPositiveNumber)
+		return sdkerrors.NewInvalidValueError("port", sdkerrors.ErrNonPositiveNumber)
 	}
 
 	return nil
@@ -43,7 +77,7 @@ func (cfg ServerConfig) Validate() error {
 // Validate validates a [HealthConfig] and returns an error if validation is failed.
 func (cfg HealthConfig) Validate() error {
 	if strings.TrimSpace(cfg.Path) == "" {
-		return sdkerrors.NewInvalidValueError("Path", sdkerrors.ErrEmptyValue)
+		return sdkerrors.NewInvalidValueError("path", sdkerrors.ErrEmptyValue)
 	}
 
 	return nil
@@ -52,7 +86,41 @@ func (cfg HealthConfig) Validate() error {
 // Validate validates a [SpecConfig] and returns an error if validation is failed.
 func (cfg SpecConfig) Validate() error {
 	if strings.TrimSpace(cfg.FilePath) == "" {
-		return sdkerrors.NewInvalidValueError("FilePath", sdkerrors.ErrEmptyValue)
+		return sdkerrors.NewInvalidValueError("file_path", sdkerrors.ErrEmptyValue)
+	}
+
+	return nil
+}
+
+// Validate validates an [AggregatorConfig] and returns an error if validation is failed.
+func (cfg AggregatorConfig) Validate() error {
+	if cfg.Timeout < 0 {
+		return sdkerrors.NewInvalidValueError("timeout", errors.New("should be greater than or equal to zero"))
+	}
+
+	switch cfg.Policy {
+	case AggregationPolicyStrict, AggregationPolicyBestEffort:
+	case AggregationPolicyQuorum:
+		if cfg.Quorum <= 0 {
+			return sdkerrors.NewInvalidValueError("quorum", sdkerrors.ErrNonPositiveNumber)
+		}
+	default:
+		return sdkerrors.NewInvalidValueError("policy", fmt.Errorf("unsupported aggregation policy %q", cfg.Policy))
+	}
+
+	if err := cfg.Merge.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("merge", err)
+	}
+
+	return nil
+}
+
+// Validate validates a [MergeConfig] and returns an error if validation is failed.
+func (cfg MergeConfig) Validate() error {
+	switch cfg.Strategy {
+	case MergeStrategyConcat, MergeStrategyDedup:
+	default:
+		return sdkerrors.NewInvalidValueError("strategy", fmt.Errorf("unsupported merge strategy %q", cfg.Strategy))
 	}
 
 	return nil
@@ -60,8 +128,22 @@ func (cfg SpecConfig) Validate() error {
 
 // Validate validates a [ScrapersConfig] and returns an error if validation is failed.
 func (cfg ScrapersConfig) Validate() error {
-	if err := cfg.Grob.Validate(); err != nil {
-		return sdkerrors.NewInvalidValueError("Grob", err)
+	if len(cfg) == 0 {
+		return sdkerrors.NewInvalidValueError("scrapers", sdkerrors.ErrEmptyValue)
+	}
+
+	names := make(map[string]struct{}, len(cfg))
+	for i, s := range cfg {
+		key := fmt.Sprintf("scrapers[%d]", i)
+		if err := s.Validate(); err != nil {
+			return sdkerrors.NewInvalidValueError(key, err)
+		}
+
+		if _, ok := names[s.Name]; ok {
+			return sdkerrors.NewInvalidValueError(key, sdkerrors.NewInvalidValueError("name", fmt.Errorf("duplicate scraper name %q", s.Name)))
+		}
+
+		names[s.Name] = struct{}{}
 	}
 
 	return nil
@@ -69,8 +151,239 @@ func (cfg ScrapersConfig) Validate() error {
 
 // Validate validates a [ScraperConfig] and returns an error if validation is failed.
 func (cfg ScraperConfig) Validate() error {
+	if strings.TrimSpace(cfg.Name) == "" {
+		return sdkerrors.NewInvalidValueError("name", sdkerrors.ErrEmptyValue)
+	}
+
+	if strings.Contains(cfg.Name, ":") {
+		return sdkerrors.NewInvalidValueError("name", errors.New(`should not contain ":", it separates the scraper name in song ids`))
+	}
+
 	if strings.TrimSpace(cfg.BaseURL) == "" {
-		return sdkerrors.NewInvalidValueError("BaseURL", sdkerrors.ErrEmptyValue)
+		return sdkerrors.NewInvalidValueError("base_url", sdkerrors.ErrEmptyValue)
+	}
+
+	if strings.TrimSpace(cfg.Parser) == "" {
+		return sdkerrors.NewInvalidValueError("parser", sdkerrors.ErrEmptyValue)
+	}
+
+	if strings.TrimSpace(cfg.Encoding) == "" {
+		return sdkerrors.NewInvalidValueError("encoding", sdkerrors.ErrEmptyValue)
+	}
+
+	if _, err := htmlindex.Get(cfg.Encoding); err != nil {
+		return sdkerrors.NewInvalidValueError("encoding", fmt.Errorf("unsupported encoding %q", cfg.Encoding))
+	}
+
+	if cfg.MaxBodySize < 0 {
+		return sdkerrors.NewInvalidValueError("max_body_size", errors.New("should be greater than or equal to zero"))
+	}
+
+	for i, t := range cfg.ContentTypes {
+		if _, params, err := mime.ParseMediaType(t); err != nil || len(params) != 0 {
+			return sdkerrors.NewInvalidValueError(fmt.Sprintf("content_types[%d]", i), fmt.Errorf("invalid media type %q", t))
+		}
+	}
+
+	if err := cfg.Retry.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("retry", err)
+	}
+
+	if err := cfg.Cache.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("cache", err)
+	}
+
+	if err := cfg.RateLimit.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("rate_limit", err)
+	}
+
+	if err := cfg.CircuitBreaker.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("circuit_breaker", err)
+	}
+
+	if err := cfg.Fixtures.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("fixtures", err)
+	}
+
+	if err := cfg.Client.Validate(); err != nil {
+		return sdkerrors.NewInvalidValueError("client", err)
+	}
+
+	if err := cfg.Robots.Validate(cfg.Client); err != nil {
+		return sdkerrors.NewInvalidValueError("robots", err)
+	}
+
+	return nil
+}
+
+// Validate validates a [RetryConfig] and returns an error if validation is failed.
+func (cfg RetryConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Attempts <= 0 {
+		return sdkerrors.NewInvalidValueError("attempts", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	switch cfg.Strategy {
+	case RetryStrategyConstant, RetryStrategyLinear, RetryStrategyExponential, RetryStrategyDecorrelatedJitter:
+	default:
+		return sdkerrors.NewInvalidValueError("strategy", fmt.Errorf("unsupported retry strategy %q", cfg.Strategy))
+	}
+
+	if cfg.MinInterval <= 0 {
+		return sdkerrors.NewInvalidValueError("min_interval", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	if cfg.MaxInterval < cfg.MinInterval {
+		return sdkerrors.NewInvalidValueError("max_interval", errors.New("should be greater than or equal to min_interval"))
+	}
+
+	switch cfg.Strategy {
+	case RetryStrategyLinear, RetryStrategyExponential:
+		if cfg.Factor <= 0 {
+			return sdkerrors.NewInvalidValueError("factor", sdkerrors.ErrNonPositiveNumber)
+		}
+	}
+
+	if cfg.MaxJitterInterval < 0 || cfg.MaxJitterInterval > cfg.MaxInterval {
+		return sdkerrors.NewInvalidValueError("max_jitter_interval", errors.New("should be between zero and max_interval"))
+	}
+
+	return nil
+}
+
+// Validate validates a [CacheConfig] and returns an error if validation is failed.
+func (cfg CacheConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.Type {
+	case CacheTypeFile:
+		if strings.TrimSpace(cfg.Dir) == "" {
+			return sdkerrors.NewInvalidValueError("dir", sdkerrors.ErrEmptyValue)
+		}
+	case CacheTypeMemory:
+	default:
+		return sdkerrors.NewInvalidValueError("type", fmt.Errorf("unsupported cache type %q", cfg.Type))
+	}
+
+	if cfg.TTL <= 0 {
+		return sdkerrors.NewInvalidValueError("ttl", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	if cfg.MaxSize <= 0 {
+		return sdkerrors.NewInvalidValueError("max_size", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	return nil
+}
+
+// Validate validates a [RateLimitConfig] and returns an error if validation is failed.
+func (cfg RateLimitConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.RPS <= 0 {
+		return sdkerrors.NewInvalidValueError("rps", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	if cfg.Burst <= 0 {
+		return sdkerrors.NewInvalidValueError("burst", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	return nil
+}
+
+// Validate validates a [RobotsConfig] against the [ClientConfig] sending the requests
+// and returns an error if validation is failed.
+func (cfg RobotsConfig) Validate(client ClientConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	// hint: robots.txt groups are matched by the product token of the user agent
+	if client.UserAgent != "" && strings.IndexAny(client.UserAgent, "/ ") == 0 {
+		return sdkerrors.NewInvalidValueError("enabled", errors.New("requires the client user agent to start with a product token"))
+	}
+
+	return nil
+}
+
+// Validate validates a [CircuitBreakerConfig] and returns an error if validation is failed.
+func (cfg CircuitBreakerConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Threshold <= 0 {
+		return sdkerrors.NewInvalidValueError("threshold", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	if cfg.Cooldown <= 0 {
+		return sdkerrors.NewInvalidValueError("cooldown", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	return nil
+}
+
+// Validate validates a [FixturesConfig] and returns an error if validation is failed.
+func (cfg FixturesConfig) Validate() error {
+	switch cfg.Mode {
+	case "":
+		return nil
+	case FixturesModeRecord, FixturesModeReplay:
+	default:
+		return sdkerrors.NewInvalidValueError("mode", fmt.Errorf("unsupported fixtures mode %q", cfg.Mode))
+	}
+
+	if strings.TrimSpace(cfg.Dir) == "" {
+		return sdkerrors.NewInvalidValueError("dir", sdkerrors.ErrEmptyValue)
+	}
+
+	return nil
+}
+
+// Validate validates a [ClientConfig] and returns an error if validation is failed.
+func (cfg ClientConfig) Validate() error {
+	if cfg.Timeout < 0 {
+		return sdkerrors.NewInvalidValueError("timeout", errors.New("should be greater than or equal to zero"))
+	}
+
+	for i, h := range cfg.Headers {
+		if name, _, ok := strings.Cut(h, ":"); !ok || strings.TrimSpace(name) == "" {
+			return sdkerrors.NewInvalidValueError(fmt.Sprintf("headers[%d]", i), errors.New(`should be in the "Name: value" format`))
+		}
+	}
+
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return sdkerrors.NewInvalidValueError("proxy_url", err)
+		}
+
+		if u.Host == "" {
+			return sdkerrors.NewInvalidValueError("proxy_url", errors.New("should be an absolute url"))
+		}
+	}
+
+	if cfg.MaxIdleConns < 0 {
+		return sdkerrors.NewInvalidValueError("max_idle_conns", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.MaxIdleConnsPerHost < 0 {
+		return sdkerrors.NewInvalidValueError("max_idle_conns_per_host", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.MaxConnsPerHost < 0 {
+		return sdkerrors.NewInvalidValueError("max_conns_per_host", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.IdleConnTimeout < 0 {
+		return sdkerrors.NewInvalidValueError("idle_conn_timeout", errors.New("should be greater than or equal to zero"))
 	}
 
 	return nil
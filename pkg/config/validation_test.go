@@ -2,14 +2,16 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 func TestConfig_Validate(t *testing.T) {
 	type fields struct {
-		Server   ServerConfig
-		Health   HealthConfig
-		Spec     SpecConfig
-		Scrapers ScrapersConfig
+		Server     ServerConfig
+		Health     HealthConfig
+		Spec       SpecConfig
+		Aggregator AggregatorConfig
+		Scrapers   ScrapersConfig
 	}
 	tests := []struct {
 		name    string
@@ -30,9 +32,14 @@ func TestConfig_Validate(t *testing.T) {
 				Spec: SpecConfig{
 					FilePath: "./api/openapi.json",
 				},
+				Aggregator: DefaultConfig.Aggregator,
 				Scrapers: ScrapersConfig{
-					Grob: ScraperConfig{
-						BaseURL: "https://test.com/",
+					{
+						Name:     "grob",
+						Parser:   "grob",
+						BaseURL:  "https://test.com/",
+						Encoding: "windows-1251",
+						Client:   DefaultClientConfig,
 					},
 				},
 			},
@@ -42,7 +49,9 @@ func TestConfig_Validate(t *testing.T) {
 			fields: fields{
 				Server: ServerConfig{},
 				Scrapers: ScrapersConfig{
-					Grob: ScraperConfig{
+					{
+						Name:    "grob",
+						Parser:  "grob",
 						BaseURL: "https://test.com/",
 					},
 				},
@@ -61,7 +70,9 @@ func TestConfig_Validate(t *testing.T) {
 					FilePath: "./api/openapi.json",
 				},
 				Scrapers: ScrapersConfig{
-					Grob: ScraperConfig{
+					{
+						Name:    "grob",
+						Parser:  "grob",
 						BaseURL: "https://test.com/",
 					},
 				},
@@ -81,14 +92,86 @@ func TestConfig_Validate(t *testing.T) {
 				},
 				Spec: SpecConfig{},
 				Scrapers: ScrapersConfig{
-					Grob: ScraperConfig{
+					{
+						Name:    "grob",
+						Parser:  "grob",
 						BaseURL: "https://test.com/",
 					},
 				},
 			},
 			wantErr: true,
 		},
-
+		{
+			name: "err  invalid aggregator",
+			fields: fields{
+				Server: ServerConfig{
+					Host: "localhost",
+					Port: 8080,
+				},
+				Health: HealthConfig{
+					Enabled: true,
+					Path:    "/health",
+				},
+				Spec: SpecConfig{
+					FilePath: "./api/openapi.json",
+				},
+				Aggregator: AggregatorConfig{
+					Timeout: -1,
+				},
+				Scrapers: DefaultConfig.Scrapers,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  quorum exceeds scrapers",
+			fields: fields{
+				Server: ServerConfig{
+					Host: "localhost",
+					Port: 8080,
+				},
+				Health: HealthConfig{
+					Enabled: true,
+					Path:    "/health",
+				},
+				Spec: SpecConfig{
+					FilePath: "./api/openapi.json",
+				},
+				Aggregator: AggregatorConfig{
+					Policy: AggregationPolicyQuorum,
+					Quorum: 2,
+					Merge: MergeConfig{
+						Strategy: MergeStrategyDedup,
+					},
+				},
+				Scrapers: DefaultConfig.Scrapers,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  unknown scraper in merge priority",
+			fields: fields{
+				Server: ServerConfig{
+					Host: "localhost",
+					Port: 8080,
+				},
+				Health: HealthConfig{
+					Enabled: true,
+					Path:    "/health",
+				},
+				Spec: SpecConfig{
+					FilePath: "./api/openapi.json",
+				},
+				Aggregator: AggregatorConfig{
+					Policy: AggregationPolicyStrict,
+					Merge: MergeConfig{
+						Strategy: MergeStrategyDedup,
+						Priority: []string{"unknown"},
+					},
+				},
+				Scrapers: DefaultConfig.Scrapers,
+			},
+			wantErr: true,
+		},
 		{
 			name: "err  invalid scrapers",
 			fields: fields{
@@ -111,8 +194,11 @@ func TestConfig_Validate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := Config{
-				Server:   tt.fields.Server,
-				Scrapers: tt.fields.Scrapers,
+				Server:     tt.fields.Server,
+				Health:     tt.fields.Health,
+				Spec:       tt.fields.Spec,
+				Aggregator: tt.fields.Aggregator,
+				Scrapers:   tt.fields.Scrapers,
 			}
 			if err := cfg.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -242,9 +328,12 @@ func TestSpecConfig_Validate(t *testing.T) {
 	}
 }
 
-func TestScrapersConfig_Validate(t *testing.T) {
+func TestAggregatorConfig_Validate(t *testing.T) {
 	type fields struct {
-		Grob ScraperConfig
+		Timeout time.Duration
+		Policy  string
+		Quorum  int
+		Merge   MergeConfig
 	}
 	tests := []struct {
 		name    string
@@ -254,25 +343,193 @@ func TestScrapersConfig_Validate(t *testing.T) {
 		{
 			name: "ok",
 			fields: fields{
-				Grob: ScraperConfig{
-					BaseURL: "https://test.com/",
+				Timeout: time.Minute,
+				Policy:  AggregationPolicyStrict,
+				Merge: MergeConfig{
+					Strategy: MergeStrategyDedup,
+				},
+			},
+		},
+		{
+			name: "ok  no timeout",
+			fields: fields{
+				Timeout: 0,
+				Policy:  AggregationPolicyBestEffort,
+				Merge: MergeConfig{
+					Strategy: MergeStrategyConcat,
+				},
+			},
+		},
+		{
+			name: "ok  quorum",
+			fields: fields{
+				Timeout: time.Minute,
+				Policy:  AggregationPolicyQuorum,
+				Quorum:  2,
+				Merge: MergeConfig{
+					Strategy: MergeStrategyDedup,
+				},
+			},
+		},
+		{
+			name: "err  negative timeout",
+			fields: fields{
+				Timeout: -time.Second,
+				Policy:  AggregationPolicyStrict,
+				Merge: MergeConfig{
+					Strategy: MergeStrategyDedup,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  unsupported policy",
+			fields: fields{
+				Timeout: time.Minute,
+				Policy:  "unknown",
+				Merge: MergeConfig{
+					Strategy: MergeStrategyDedup,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  non-positive quorum",
+			fields: fields{
+				Timeout: time.Minute,
+				Policy:  AggregationPolicyQuorum,
+				Merge: MergeConfig{
+					Strategy: MergeStrategyDedup,
 				},
 			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid merge",
+			fields: fields{
+				Timeout: time.Minute,
+				Policy:  AggregationPolicyStrict,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := AggregatorConfig{
+				Timeout: tt.fields.Timeout,
+				Policy:  tt.fields.Policy,
+				Quorum:  tt.fields.Quorum,
+				Merge:   tt.fields.Merge,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AggregatorConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeConfig_Validate(t *testing.T) {
+	type fields struct {
+		Strategy string
+		Priority []string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Strategy: MergeStrategyDedup,
+				Priority: []string{"grob"},
+			},
 		},
 		{
-			name: "err  invalid grob scraper config",
+			name: "err  unsupported strategy",
 			fields: fields{
-				Grob: ScraperConfig{},
+				Strategy: "unknown",
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cfg := ScrapersConfig{
-				Grob: tt.fields.Grob,
+			cfg := MergeConfig{
+				Strategy: tt.fields.Strategy,
+				Priority: tt.fields.Priority,
 			}
 			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("MergeConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScrapersConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ScrapersConfig
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			cfg: ScrapersConfig{
+				{
+					Name:     "grob",
+					Parser:   "grob",
+					BaseURL:  "https://test.com/",
+					Encoding: "windows-1251",
+					Client:   DefaultClientConfig,
+				},
+				{
+					Name:     "other",
+					Parser:   "grob",
+					BaseURL:  "https://other.com/",
+					Encoding: "utf-8",
+					Client:   DefaultClientConfig,
+				},
+			},
+		},
+		{
+			name:    "err  empty list",
+			cfg:     ScrapersConfig{},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid scraper config",
+			cfg: ScrapersConfig{
+				{
+					Name:   "grob",
+					Parser: "grob",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  duplicate name",
+			cfg: ScrapersConfig{
+				{
+					Name:     "grob",
+					Parser:   "grob",
+					BaseURL:  "https://test.com/",
+					Encoding: "windows-1251",
+					Client:   DefaultClientConfig,
+				},
+				{
+					Name:     "grob",
+					Parser:   "grob",
+					BaseURL:  "https://other.com/",
+					Encoding: "windows-1251",
+					Client:   DefaultClientConfig,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("ScrapersConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -281,7 +538,19 @@ func TestScrapersConfig_Validate(t *testing.T) {
 
 func TestScraperConfig_Validate(t *testing.T) {
 	type fields struct {
-		BaseURL string
+		Name           string
+		Parser         string
+		BaseURL        string
+		Encoding       string
+		PartialResults bool
+		MaxBodySize    int64
+		ContentTypes   []string
+		Retry          RetryConfig
+		Cache          CacheConfig
+		RateLimit      RateLimitConfig
+		CircuitBreaker CircuitBreakerConfig
+		Fixtures       FixturesConfig
+		Client         ClientConfig
 	}
 	tests := []struct {
 		name    string
@@ -291,21 +560,226 @@ func TestScraperConfig_Validate(t *testing.T) {
 		{
 			name: "ok",
 			fields: fields{
-				BaseURL: "https://test.com/",
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Client:   DefaultClientConfig,
 			},
 		},
+		{
+			name: "ok  partial results",
+			fields: fields{
+				Name:           "grob",
+				Parser:         "grob",
+				BaseURL:        "https://test.com/",
+				Encoding:       "windows-1251",
+				PartialResults: true,
+				Client:         DefaultClientConfig,
+			},
+		},
+		{
+			name: "ok  body limits",
+			fields: fields{
+				Name:         "grob",
+				Parser:       "grob",
+				BaseURL:      "https://test.com/",
+				Encoding:     "windows-1251",
+				MaxBodySize:  10 << 20,
+				ContentTypes: []string{"text/html", "application/xhtml+xml"},
+				Client:       DefaultClientConfig,
+			},
+		},
+		{
+			name: "err  empty name",
+			fields: fields{
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Client:   DefaultClientConfig,
+			},
+			wantErr: true,
+		},
 		{
 			name: "err  empty base url",
 			fields: fields{
+				Name:    "grob",
+				Parser:  "grob",
 				BaseURL: "",
 			},
 			wantErr: true,
 		},
+		{
+			name: "err  name with colon",
+			fields: fields{
+				Name:     "grob:1",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Client:   DefaultClientConfig,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty parser",
+			fields: fields{
+				Name:     "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Client:   DefaultClientConfig,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty encoding",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "",
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  unsupported encoding",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "unknown",
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  negative max body size",
+			fields: fields{
+				Name:        "grob",
+				Parser:      "grob",
+				BaseURL:     "https://test.com/",
+				Encoding:    "windows-1251",
+				MaxBodySize: -1,
+				Client:      DefaultClientConfig,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid content type",
+			fields: fields{
+				Name:         "grob",
+				Parser:       "grob",
+				BaseURL:      "https://test.com/",
+				Encoding:     "windows-1251",
+				ContentTypes: []string{"text/html; charset=utf-8"},
+				Client:       DefaultClientConfig,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty content type",
+			fields: fields{
+				Name:         "grob",
+				Parser:       "grob",
+				BaseURL:      "https://test.com/",
+				Encoding:     "windows-1251",
+				ContentTypes: []string{""},
+				Client:       DefaultClientConfig,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid retry",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Retry: RetryConfig{
+					Enabled: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid cache",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Cache: CacheConfig{
+					Enabled: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid rate limit",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				RateLimit: RateLimitConfig{
+					Enabled: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid circuit breaker",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				CircuitBreaker: CircuitBreakerConfig{
+					Enabled: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid fixtures",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Fixtures: FixturesConfig{
+					Mode: FixturesModeReplay,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid client",
+			fields: fields{
+				Name:     "grob",
+				Parser:   "grob",
+				BaseURL:  "https://test.com/",
+				Encoding: "windows-1251",
+				Client: ClientConfig{
+					Timeout: -time.Second,
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := ScraperConfig{
-				BaseURL: tt.fields.BaseURL,
+				Name:           tt.fields.Name,
+				Parser:         tt.fields.Parser,
+				BaseURL:        tt.fields.BaseURL,
+				Encoding:       tt.fields.Encoding,
+				PartialResults: tt.fields.PartialResults,
+				MaxBodySize:    tt.fields.MaxBodySize,
+				ContentTypes:   tt.fields.ContentTypes,
+				Retry:          tt.fields.Retry,
+				Cache:          tt.fields.Cache,
+				RateLimit:      tt.fields.RateLimit,
+				CircuitBreaker: tt.fields.CircuitBreaker,
+				Fixtures:       tt.fields.Fixtures,
+				Client:         tt.fields.Client,
 			}
 			if err := cfg.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("ScraperConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -313,3 +787,537 @@ func TestScraperConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheConfig_Validate(t *testing.T) {
+	type fields struct {
+		Enabled bool
+		Type    string
+		Dir     string
+		TTL     time.Duration
+		MaxSize int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok  file",
+			fields: fields{
+				Enabled: true,
+				Type:    CacheTypeFile,
+				Dir:     "/tmp/cache",
+				TTL:     time.Hour,
+				MaxSize: 1024,
+			},
+		},
+		{
+			name: "ok  memory",
+			fields: fields{
+				Enabled: true,
+				Type:    CacheTypeMemory,
+				TTL:     time.Hour,
+				MaxSize: 1024,
+			},
+		},
+		{
+			name: "ok  disabled",
+			fields: fields{
+				Enabled: false,
+			},
+		},
+		{
+			name: "err  unsupported type",
+			fields: fields{
+				Enabled: true,
+				Type:    "redis",
+				TTL:     time.Hour,
+				MaxSize: 1024,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty dir",
+			fields: fields{
+				Enabled: true,
+				Type:    CacheTypeFile,
+				Dir:     "",
+				TTL:     time.Hour,
+				MaxSize: 1024,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  ttl is non-positive number",
+			fields: fields{
+				Enabled: true,
+				Type:    CacheTypeMemory,
+				TTL:     0,
+				MaxSize: 1024,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  max size is non-positive number",
+			fields: fields{
+				Enabled: true,
+				Type:    CacheTypeMemory,
+				TTL:     time.Hour,
+				MaxSize: 0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CacheConfig{
+				Enabled: tt.fields.Enabled,
+				Type:    tt.fields.Type,
+				Dir:     tt.fields.Dir,
+				TTL:     tt.fields.TTL,
+				MaxSize: tt.fields.MaxSize,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("CacheConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	type fields struct {
+		Enabled bool
+		RPS     float64
+		Burst   int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Enabled: true,
+				RPS:     5,
+				Burst:   10,
+			},
+		},
+		{
+			name: "ok  disabled",
+			fields: fields{
+				Enabled: false,
+			},
+		},
+		{
+			name: "err  rps is non-positive number",
+			fields: fields{
+				Enabled: true,
+				RPS:     0,
+				Burst:   10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  burst is non-positive number",
+			fields: fields{
+				Enabled: true,
+				RPS:     5,
+				Burst:   0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := RateLimitConfig{
+				Enabled: tt.fields.Enabled,
+				RPS:     tt.fields.RPS,
+				Burst:   tt.fields.Burst,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RateLimitConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerConfig_Validate(t *testing.T) {
+	type fields struct {
+		Enabled   bool
+		Threshold int
+		Cooldown  time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Enabled:   true,
+				Threshold: 5,
+				Cooldown:  30 * time.Second,
+			},
+		},
+		{
+			name: "ok  disabled",
+			fields: fields{
+				Enabled: false,
+			},
+		},
+		{
+			name: "err  threshold is non-positive number",
+			fields: fields{
+				Enabled:   true,
+				Threshold: 0,
+				Cooldown:  30 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  cooldown is non-positive number",
+			fields: fields{
+				Enabled:   true,
+				Threshold: 5,
+				Cooldown:  0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CircuitBreakerConfig{
+				Enabled:   tt.fields.Enabled,
+				Threshold: tt.fields.Threshold,
+				Cooldown:  tt.fields.Cooldown,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("CircuitBreakerConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRobotsConfig_Validate(t *testing.T) {
+	type fields struct {
+		Enabled bool
+	}
+	type args struct {
+		client ClientConfig
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Enabled: true,
+			},
+			args: args{
+				client: ClientConfig{
+					UserAgent: "LindenHoneyBot/1.0",
+				},
+			},
+		},
+		{
+			name: "ok  default user agent",
+			fields: fields{
+				Enabled: true,
+			},
+		},
+		{
+			name: "ok  disabled",
+			fields: fields{
+				Enabled: false,
+			},
+			args: args{
+				client: ClientConfig{
+					UserAgent: "/1.0",
+				},
+			},
+		},
+		{
+			name: "err  user agent without product token",
+			fields: fields{
+				Enabled: true,
+			},
+			args: args{
+				client: ClientConfig{
+					UserAgent: "/1.0",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := RobotsConfig{
+				Enabled: tt.fields.Enabled,
+			}
+			if err := cfg.Validate(tt.args.client); (err != nil) != tt.wantErr {
+				t.Errorf("RobotsConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFixturesConfig_Validate(t *testing.T) {
+	type fields struct {
+		Mode string
+		Dir  string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Mode: FixturesModeRecord,
+				Dir:  "./testdata",
+			},
+		},
+		{
+			name: "ok  disabled",
+			fields: fields{
+				Mode: "",
+			},
+		},
+		{
+			name: "err  unsupported mode",
+			fields: fields{
+				Mode: "unknown",
+				Dir:  "./testdata",
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty dir",
+			fields: fields{
+				Mode: FixturesModeReplay,
+				Dir:  "",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := FixturesConfig{
+				Mode: tt.fields.Mode,
+				Dir:  tt.fields.Dir,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("FixturesConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientConfig_Validate(t *testing.T) {
+	type fields struct {
+		Timeout             time.Duration
+		Headers             []string
+		ProxyURL            string
+		MaxIdleConns        int
+		MaxIdleConnsPerHost int
+		MaxConnsPerHost     int
+		IdleConnTimeout     time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Timeout:             30 * time.Second,
+				Headers:             []string{"Accept-Language: ru", "X-Empty:"},
+				ProxyURL:            "http://proxy:3128",
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		{
+			name: "ok  no timeout",
+			fields: fields{
+				Timeout: 0,
+			},
+		},
+		{
+			name: "err  negative timeout",
+			fields: fields{
+				Timeout: -time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid header",
+			fields: fields{
+				Timeout: 30 * time.Second,
+				Headers: []string{"Accept-Language"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  relative proxy url",
+			fields: fields{
+				Timeout:  30 * time.Second,
+				ProxyURL: "proxy:3128",
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  negative max idle conns",
+			fields: fields{
+				Timeout:      30 * time.Second,
+				MaxIdleConns: -1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ClientConfig{
+				Timeout:             tt.fields.Timeout,
+				Headers:             tt.fields.Headers,
+				ProxyURL:            tt.fields.ProxyURL,
+				MaxIdleConns:        tt.fields.MaxIdleConns,
+				MaxIdleConnsPerHost: tt.fields.MaxIdleConnsPerHost,
+				MaxConnsPerHost:     tt.fields.MaxConnsPerHost,
+				IdleConnTimeout:     tt.fields.IdleConnTimeout,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ClientConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryConfig_Validate(t *testing.T) {
+	type fields struct {
+		Enabled           bool
+		Attempts          int
+		Strategy          string
+		MinInterval       time.Duration
+		MaxInterval       time.Duration
+		Factor            time.Duration
+		MaxJitterInterval time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Enabled:     true,
+				Attempts:    5,
+				Strategy:    RetryStrategyExponential,
+				MinInterval: 2 * time.Second,
+				MaxInterval: 10 * time.Second,
+				Factor:      2 * time.Second,
+			},
+		},
+		{
+			name: "ok  disabled",
+			fields: fields{
+				Enabled: false,
+			},
+		},
+		{
+			name: "ok  constant strategy without factor",
+			fields: fields{
+				Enabled:           true,
+				Attempts:          3,
+				Strategy:          RetryStrategyConstant,
+				MinInterval:       time.Second,
+				MaxInterval:       time.Second,
+				MaxJitterInterval: time.Second,
+			},
+		},
+		{
+			name: "err  attempts is non-positive number",
+			fields: fields{
+				Enabled:     true,
+				Attempts:    0,
+				Strategy:    RetryStrategyExponential,
+				MinInterval: 2 * time.Second,
+				MaxInterval: 10 * time.Second,
+				Factor:      2 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  unsupported strategy",
+			fields: fields{
+				Enabled:     true,
+				Attempts:    5,
+				Strategy:    "fibonacci",
+				MinInterval: 2 * time.Second,
+				MaxInterval: 10 * time.Second,
+				Factor:      2 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  max interval is less than min interval",
+			fields: fields{
+				Enabled:     true,
+				Attempts:    5,
+				Strategy:    RetryStrategyExponential,
+				MinInterval: 10 * time.Second,
+				MaxInterval: 2 * time.Second,
+				Factor:      2 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  factor is non-positive number",
+			fields: fields{
+				Enabled:     true,
+				Attempts:    5,
+				Strategy:    RetryStrategyLinear,
+				MinInterval: 2 * time.Second,
+				MaxInterval: 10 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  max jitter interval is greater than max interval",
+			fields: fields{
+				Enabled:           true,
+				Attempts:          5,
+				Strategy:          RetryStrategyConstant,
+				MinInterval:       2 * time.Second,
+				MaxInterval:       10 * time.Second,
+				MaxJitterInterval: time.Minute,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := RetryConfig{
+				Enabled:           tt.fields.Enabled,
+				Attempts:          tt.fields.Attempts,
+				Strategy:          tt.fields.Strategy,
+				MinInterval:       tt.fields.MinInterval,
+				MaxInterval:       tt.fields.MaxInterval,
+				Factor:            tt.fields.Factor,
+				MaxJitterInterval: tt.fields.MaxJitterInterval,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RetryConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package aggregator
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/linden-honey/linden-honey-api-go/pkg/song"
 
@@ -12,82 +14,378 @@ import (
 
 // Aggregator is an implementation of the [scraper.Service]
 // that aggregates results from multiple source.
+//
+// Sources are queried concurrently, results are merged in the order of the sources,
+// so the output doesn't depend on which source responds first.
+// Song ids are qualified with the source names, see [QualifyID].
 type Aggregator struct {
-	services []scraper.Service
+	sources []Source
+	timeout time.Duration
+	merge   MergeStrategy
+	policy  Policy
+}
+
+// Source is a named [scraper.Service] aggregated by the [Aggregator].
+type Source struct {
+	Name    string
+	Service scraper.Service
 }
 
 // New returns a pointer to the new instance of [Aggregator] or an error.
-func New(services ...scraper.Service) (*Aggregator, error) {
-	return &Aggregator{
-		services: services,
-	}, nil
+func New(sources []Source, opts ...Option) (*Aggregator, error) {
+	a := &Aggregator{
+		sources: sources,
+		merge:   Concat,
+		policy:  PolicyStrict,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Option set optional parameters for the [Aggregator].
+type Option func(*Aggregator)
+
+// WithTimeout sets the deadline of each source call relative to the start of the request,
+// zero timeout means the calls are limited only by the request context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(a *Aggregator) {
+		a.timeout = timeout
+	}
 }
 
-// GetSong tries to scrape a song by id from multiple services
+// WithMergeStrategy sets the strategy merging songs and previews of the sources, [Concat] is used by default.
+func WithMergeStrategy(merge MergeStrategy) Option {
+	return func(a *Aggregator) {
+		a.merge = merge
+	}
+}
+
+// WithPolicy sets the policy of tolerating failures of the sources, [PolicyStrict] is used by default.
+func WithPolicy(policy Policy) Option {
+	return func(a *Aggregator) {
+		a.policy = policy
+	}
+}
+
+// result is an outcome of a single source call.
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// sourceContext returns the context of a single source call.
+func (a *Aggregator) sourceContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if a.timeout > 0 {
+		return context.WithTimeout(ctx, a.timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// fanOut calls fn for each source concurrently and returns the results in the order of the sources.
+func fanOut[T any](
+	ctx context.Context,
+	a *Aggregator,
+	fn func(ctx context.Context, src Source) (T, error),
+) []result[T] {
+	res := make([]result[T], len(a.sources))
+
+	var wg sync.WaitGroup
+	for i, src := range a.sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+
+			ctx, cancel := a.sourceContext(ctx)
+			defer cancel()
+
+			res[i].value, res[i].err = fn(ctx, src)
+		}(i, src)
+	}
+	wg.Wait()
+
+	return res
+}
+
+// GetSong tries to scrape a song by id from multiple sources
 // and returns a pointer to the new instance of [song.Song] or an error.
+//
+// A qualified id, see [QualifyID], is routed to its source only.
+// Any other id is requested as a bare id from all the sources, including an id with the [IDSeparator]
+// not prefixed with a source name, since the own ids of a source may contain the separator.
+// The song of the first source in order that succeeds is returned and calls to the rest of the sources are cancelled.
+// The id of the returned song is qualified with its source.
 func (a *Aggregator) GetSong(ctx context.Context, id string) (*song.Song, error) {
-	errs := make([]error, 0)
-	for i, svc := range a.services {
-		s, err := svc.GetSong(ctx, id)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("failed to get the song from services[%d]: %w", i, err))
-			continue
+	if src, sourceID, ok := a.splitID(id); ok {
+		return a.getSourceSong(ctx, src, sourceID)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type indexed struct {
+		i int
+		result[*song.Song]
+	}
+
+	resc := make(chan indexed, len(a.sources)) // hint: buffered to not leak abandoned calls
+	for i, src := range a.sources {
+		go func(i int, svc scraper.Service) {
+			ctx, cancel := a.sourceContext(ctx)
+			defer cancel()
+
+			s, err := svc.GetSong(ctx, id)
+			resc <- indexed{i: i, result: result[*song.Song]{value: s, err: err}}
+		}(i, src.Service)
+	}
+
+	res := make([]*result[*song.Song], len(a.sources))
+	next := 0
+	for range a.sources {
+		r := <-resc
+		res[r.i] = &r.result
+
+		for ; next < len(res) && res[next] != nil; next++ {
+			if res[next].err == nil {
+				s := *res[next].value
+				s.ID = QualifyID(a.sources[next].Name, s.ID)
+
+				return &s, nil
+			}
+		}
+	}
+
+	errs := make([]error, 0, len(res))
+	for i, r := range res {
+		errs = append(errs, &scraper.SourceError{Source: a.sources[i].Name, Err: r.err})
+	}
+
+	return nil, NewAggregationError("failed to get the song from any source", errs...)
+}
+
+// splitID splits the qualified id of a song into the source and the source id,
+// ok is false if the id is not prefixed with the name of a source.
+func (a *Aggregator) splitID(id string) (Source, string, bool) {
+	name, sourceID, ok := SplitID(id)
+	if !ok {
+		return Source{}, "", false
+	}
+
+	for _, src := range a.sources {
+		if src.Name == name {
+			return src, sourceID, true
 		}
+	}
+
+	return Source{}, "", false
+}
 
-		return s, nil
+// getSourceSong scrapes a song by the source id from the source.
+func (a *Aggregator) getSourceSong(ctx context.Context, src Source, id string) (*song.Song, error) {
+	ctx, cancel := a.sourceContext(ctx)
+	defer cancel()
+
+	s, err := src.Service.GetSong(ctx, id)
+	if err != nil {
+		return nil, NewAggregationError("failed to get the song", &scraper.SourceError{Source: src.Name, Err: err})
 	}
 
-	return nil, NewAggregationError("failed to get the song from any service", errs...)
+	cp := *s
+	cp.ID = QualifyID(src.Name, cp.ID)
+
+	return &cp, nil
 }
 
-// GetSongs scrapes and aggregates all songs from multiple services
+// GetSongs scrapes and aggregates all songs from multiple sources
 // and returns a slice of [song.Song] instances or an error.
+//
+// Songs of the sources are merged by the [MergeStrategy], see [WithMergeStrategy].
+// Partial results of the sources are aggregated as well,
+// in this case the failures are returned as a [*scraper.PartialResultError]
+// along with the failed sources tolerated by the [Policy], see [WithPolicy].
 func (a *Aggregator) GetSongs(ctx context.Context) ([]song.Song, error) {
-	res := make([]song.Song, 0)
-	errs := make([]error, 0)
-	for i, svc := range a.services {
-		ss, err := svc.GetSongs(ctx)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("failed to get songs from services[%d]: %w", i, err))
+	results := fanOut(ctx, a, func(ctx context.Context, src Source) ([]song.Song, error) {
+		return src.Service.GetSongs(ctx)
+	})
+
+	sources := make([]SourceSongs, 0, len(results))
+	failed := make([]*scraper.SourceError, 0)
+	perr := new(scraper.PartialResultError)
+	for i, r := range results {
+		if rerr := new(scraper.PartialResultError); errors.As(r.err, &rerr) {
+			perr.Failures = append(perr.Failures, qualifyFailures(a.sources[i].Name, rerr.Failures)...)
+			perr.Sources = append(perr.Sources, rerr.Sources...)
+		} else if r.err != nil {
+			failed = append(failed, &scraper.SourceError{Source: a.sources[i].Name, Err: r.err})
 			continue
 		}
 
-		res = append(res, ss...)
+		sources = append(sources, SourceSongs{
+			Source: a.sources[i].Name,
+			Songs:  qualifySongs(a.sources[i].Name, r.value),
+		})
 	}
 
-	if len(errs) != 0 {
-		return nil, NewAggregationError("failed to aggregate songs", errs...)
+	if err := a.apply("failed to aggregate songs", failed); err != nil {
+		return nil, err
 	}
 
+	res := a.merge.Merge(sources)
 	sort.SliceStable(res, func(i, j int) bool {
 		return res[i].Title < res[j].Title
 	})
 
+	perr.Sources = append(perr.Sources, failed...)
+	if len(perr.Failures) != 0 || len(perr.Sources) != 0 {
+		return res, perr
+	}
+
 	return res, nil
 }
 
-// GetPreviews scrapes songs metadata from multiple services
+// StreamSongs scrapes all songs from multiple sources
+// and calls fn for each song as soon as it is scraped or returns an error.
+//
+// Sources are streamed concurrently, fn is called sequentially in the order songs are scraped.
+// Songs are not merged, so the same song may be streamed by several sources.
+// An error returned by fn cancels all sources and is returned as is.
+// The rest of the sources are cancelled as soon as the failed ones don't satisfy the [Policy].
+// Songs already passed to fn are not revoked if some source fails afterwards.
+// Partial results of the sources are streamed as well,
+// in this case the failures are returned as a [*scraper.PartialResultError]
+// along with the failed sources tolerated by the [Policy], see [WithPolicy].
+func (a *Aggregator) StreamSongs(ctx context.Context, fn func(s song.Song) error) error {
+	sctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		fnErr   error
+		failedN int
+		unmet   bool
+	)
+	results := fanOut(sctx, a, func(ctx context.Context, src Source) (struct{}, error) {
+		err := src.Service.StreamSongs(ctx, func(s song.Song) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if fnErr != nil {
+				return fnErr
+			}
+
+			s.ID = QualifyID(src.Name, s.ID)
+
+			if fnErr = fn(s); fnErr != nil {
+				cancel()
+			}
+
+			return fnErr
+		})
+
+		if perr := new(scraper.PartialResultError); err != nil && !errors.As(err, &perr) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			failedN++
+			if !unmet && !a.policy.satisfied(len(a.sources)-failedN, len(a.sources)) {
+				unmet = true
+				cancel()
+			}
+		}
+
+		return struct{}{}, err
+	})
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	failed := make([]*scraper.SourceError, 0)
+	perr := new(scraper.PartialResultError)
+	for i, r := range results {
+		if rerr := new(scraper.PartialResultError); errors.As(r.err, &rerr) {
+			perr.Failures = append(perr.Failures, qualifyFailures(a.sources[i].Name, rerr.Failures)...)
+			perr.Sources = append(perr.Sources, rerr.Sources...)
+		} else if r.err != nil {
+			if unmet && ctx.Err() == nil && errors.Is(r.err, context.Canceled) {
+				continue // hint: the source is cancelled after the policy is not satisfied by the others
+			}
+			failed = append(failed, &scraper.SourceError{Source: a.sources[i].Name, Err: r.err})
+		}
+	}
+
+	if err := a.apply("failed to aggregate songs", failed); err != nil {
+		return err
+	}
+
+	perr.Sources = append(perr.Sources, failed...)
+	if len(perr.Failures) != 0 || len(perr.Sources) != 0 {
+		return perr
+	}
+
+	return nil
+}
+
+// GetPreviews scrapes songs metadata from multiple sources
 // and returns a slice of [song.Metadata] instances or an error.
+//
+// Previews of the sources are merged by the [MergeStrategy] as songs without lyrics.
+// The failed sources tolerated by the [Policy] are returned as a [*scraper.PartialResultError]
+// along with the previews of the succeeded sources, see [WithPolicy].
 func (a *Aggregator) GetPreviews(ctx context.Context) ([]song.Metadata, error) {
-	res := make([]song.Metadata, 0)
-	errs := make([]error, 0)
-	for i, svc := range a.services {
-		ps, err := svc.GetPreviews(ctx)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("failed to get previews from services[%d]: %w", i, err))
+	results := fanOut(ctx, a, func(ctx context.Context, src Source) ([]song.Metadata, error) {
+		return src.Service.GetPreviews(ctx)
+	})
+
+	sources := make([]SourceSongs, 0, len(results))
+	failed := make([]*scraper.SourceError, 0)
+	perr := new(scraper.PartialResultError)
+	for i, r := range results {
+		if rerr := new(scraper.PartialResultError); errors.As(r.err, &rerr) {
+			perr.Sources = append(perr.Sources, rerr.Sources...)
+		} else if r.err != nil {
+			failed = append(failed, &scraper.SourceError{Source: a.sources[i].Name, Err: r.err})
 			continue
 		}
 
-		res = append(res, ps...)
+		ss := make([]song.Song, 0, len(r.value))
+		for _, p := range r.value {
+			p.ID = QualifyID(a.sources[i].Name, p.ID)
+			ss = append(ss, song.Song{Metadata: p})
+		}
+
+		sources = append(sources, SourceSongs{
+			Source: a.sources[i].Name,
+			Songs:  ss,
+		})
+	}
+
+	if err := a.apply("failed to aggregate previews", failed); err != nil {
+		return nil, err
 	}
 
-	if len(errs) != 0 {
-		return nil, NewAggregationError("failed to aggregate previews", errs...)
+	merged := a.merge.Merge(sources)
+	res := make([]song.Metadata, 0, len(merged))
+	for _, s := range merged {
+		res = append(res, s.Metadata)
 	}
 
 	sort.SliceStable(res, func(i, j int) bool {
 		return res[i].Title < res[j].Title
 	})
 
+	perr.Sources = append(perr.Sources, failed...)
+	if len(perr.Sources) != 0 {
+		return res, perr
+	}
+
 	return res, nil
 }
@@ -0,0 +1,793 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
+)
+
+// fakeService serves the configured songs or fails until the context is done.
+type fakeService struct {
+	songs []song.Song
+	err   error
+	// after, if set, blocks each call until it's closed, a never closed channel blocks until the context is done.
+	after chan struct{}
+	// done, if set, is closed when a call is completed.
+	done chan struct{}
+	// barrier, if set, blocks each call until all services sharing it are called.
+	barrier *sync.WaitGroup
+}
+
+func (svc *fakeService) wait(ctx context.Context) error {
+	if svc.barrier != nil {
+		svc.barrier.Done()
+
+		done := make(chan struct{})
+		go func() {
+			svc.barrier.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if svc.after != nil {
+		select {
+		case <-svc.after:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return svc.err
+}
+
+func (svc *fakeService) finish() {
+	if svc.done != nil {
+		close(svc.done)
+	}
+}
+
+func (svc *fakeService) GetSong(ctx context.Context, id string) (*song.Song, error) {
+	defer svc.finish()
+
+	if err := svc.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, s := range svc.songs {
+		if s.ID == id {
+			s := s
+			return &s, nil
+		}
+	}
+
+	return nil, scraper.ErrNotFound
+}
+
+func (svc *fakeService) GetSongs(ctx context.Context) ([]song.Song, error) {
+	defer svc.finish()
+
+	if err := svc.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return append([]song.Song(nil), svc.songs...), nil
+}
+
+func (svc *fakeService) StreamSongs(ctx context.Context, fn func(s song.Song) error) error {
+	defer svc.finish()
+
+	if err := svc.wait(ctx); err != nil {
+		return err
+	}
+
+	for _, s := range svc.songs {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *fakeService) GetPreviews(ctx context.Context) ([]song.Metadata, error) {
+	defer svc.finish()
+
+	if err := svc.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	ps := make([]song.Metadata, 0, len(svc.songs))
+	for _, s := range svc.songs {
+		ps = append(ps, s.Metadata)
+	}
+
+	return ps, nil
+}
+
+func newSong(id, title string) song.Song {
+	return song.Song{
+		Metadata: song.Metadata{
+			ID:    id,
+			Title: title,
+		},
+	}
+}
+
+// newSources returns the sources named by their index, e.g. "s0".
+func newSources(svcs ...scraper.Service) []Source {
+	srcs := make([]Source, 0, len(svcs))
+	for i, svc := range svcs {
+		srcs = append(srcs, Source{Name: fmt.Sprintf("s%d", i), Service: svc})
+	}
+
+	return srcs
+}
+
+// inOrder makes the services complete in the order of the arguments, each one after the previous one.
+func inOrder(svcs ...*fakeService) {
+	for i := 1; i < len(svcs); i++ {
+		svcs[i-1].done = make(chan struct{})
+		svcs[i].after = svcs[i-1].done
+	}
+}
+
+func newBarrier(n int) *sync.WaitGroup {
+	wg := new(sync.WaitGroup)
+	wg.Add(n)
+
+	return wg
+}
+
+func TestNew(t *testing.T) {
+	type fields struct {
+		sources []Source
+		opts    []Option
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				sources: newSources(&fakeService{}, &fakeService{}),
+				opts: []Option{
+					WithTimeout(time.Second),
+					WithMergeStrategy(NewDedup("s1")),
+				},
+			},
+		},
+		{
+			name:    "err  no sources",
+			wantErr: true,
+		},
+		{
+			name: "err  nil service",
+			fields: fields{
+				sources: newSources(scraper.Service(nil)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty source name",
+			fields: fields{
+				sources: []Source{{Service: &fakeService{}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  source name with id separator",
+			fields: fields{
+				sources: []Source{{Name: "a:b", Service: &fakeService{}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  duplicate source name",
+			fields: fields{
+				sources: []Source{{Name: "a", Service: &fakeService{}}, {Name: "a", Service: &fakeService{}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  negative timeout",
+			fields: fields{
+				sources: newSources(&fakeService{}),
+				opts:    []Option{WithTimeout(-time.Second)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  quorum exceeds sources",
+			fields: fields{
+				sources: newSources(&fakeService{}),
+				opts:    []Option{WithPolicy(PolicyQuorum(2))},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  nil merge strategy",
+			fields: fields{
+				sources: newSources(&fakeService{}),
+				opts:    []Option{WithMergeStrategy(nil)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.fields.sources, tt.fields.opts...); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAggregator_GetSong(t *testing.T) {
+	type fields struct {
+		services []scraper.Service
+		timeout  time.Duration
+	}
+	tests := []struct {
+		name         string
+		id           string
+		fields       fields
+		want         *song.Song
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{
+			name: "ok  first service in order wins",
+			id:   "1",
+			fields: func() fields {
+				slow := &fakeService{songs: []song.Song{newSong("1", "slow")}}
+				fast := &fakeService{songs: []song.Song{newSong("1", "fast")}}
+				inOrder(fast, slow)
+
+				return fields{
+					services: []scraper.Service{slow, fast},
+				}
+			}(),
+			want: &song.Song{Metadata: song.Metadata{ID: "s0:1", Title: "slow"}},
+		},
+		{
+			name: "ok  fallback to the next service",
+			id:   "1",
+			fields: func() fields {
+				failed := &fakeService{err: errors.New("unavailable")}
+				fallback := &fakeService{songs: []song.Song{newSong("1", "fallback")}}
+				inOrder(failed, fallback)
+
+				return fields{
+					services: []scraper.Service{failed, fallback},
+				}
+			}(),
+			want: &song.Song{Metadata: song.Metadata{ID: "s1:1", Title: "fallback"}},
+		},
+		{
+			name: "ok  slow service times out",
+			id:   "1",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("1", "slow")}, after: make(chan struct{})},
+					&fakeService{songs: []song.Song{newSong("1", "fast")}},
+				},
+				timeout: 20 * time.Millisecond,
+			},
+			want: &song.Song{Metadata: song.Metadata{ID: "s1:1", Title: "fast"}},
+		},
+		{
+			name: "ok  qualified id routed to its source",
+			id:   "s1:1",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("1", "first")}},
+					&fakeService{songs: []song.Song{newSong("1", "second")}},
+				},
+			},
+			want: &song.Song{Metadata: song.Metadata{ID: "s1:1", Title: "second"}},
+		},
+		{
+			name: "err  qualified id not found in its source",
+			id:   "s1:1",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("1", "first")}},
+					&fakeService{},
+				},
+			},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name: "ok  bare id with the separator",
+			id:   "a:1",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{},
+					&fakeService{songs: []song.Song{newSong("a:1", "bare")}},
+				},
+			},
+			want: &song.Song{Metadata: song.Metadata{ID: "s1:a:1", Title: "bare"}},
+		},
+		{
+			name: "ok  qualified id with the separator",
+			id:   "s1:a:1",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("a:1", "first")}},
+					&fakeService{songs: []song.Song{newSong("a:1", "second")}},
+				},
+			},
+			want: &song.Song{Metadata: song.Metadata{ID: "s1:a:1", Title: "second"}},
+		},
+		{
+			name: "err  bare id with the separator not found",
+			id:   "unknown:1",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("1", "first")}},
+				},
+			},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name: "err  all services failed",
+			id:   "1",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{err: errors.New("unavailable")},
+					&fakeService{},
+				},
+			},
+			wantErr:      true,
+			wantNotFound: true, // errors.Is matches any cause
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(newSources(tt.fields.services...), WithTimeout(tt.fields.timeout))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := a.GetSong(context.Background(), tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Aggregator.GetSong() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if errors.Is(err, scraper.ErrNotFound) != tt.wantNotFound {
+				t.Errorf("Aggregator.GetSong() error = %v, wantNotFound %v", err, tt.wantNotFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Aggregator.GetSong() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregator_GetSongs(t *testing.T) {
+	type fields struct {
+		services []scraper.Service
+		timeout  time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    []song.Song
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: func() fields {
+				slow := &fakeService{songs: []song.Song{newSong("2", "b"), newSong("3", "c")}}
+				fast := &fakeService{songs: []song.Song{newSong("1", "a"), newSong("4", "b")}}
+				inOrder(fast, slow)
+
+				return fields{
+					services: []scraper.Service{slow, fast},
+				}
+			}(),
+			want: []song.Song{newSong("s1:1", "a"), newSong("s0:2", "b"), newSong("s1:4", "b"), newSong("s0:3", "c")},
+		},
+		{
+			name: "ok  concurrent",
+			fields: func() fields {
+				barrier := newBarrier(2)
+
+				return fields{
+					services: []scraper.Service{
+						&fakeService{songs: []song.Song{newSong("1", "a")}, barrier: barrier},
+						&fakeService{songs: []song.Song{newSong("2", "b")}, barrier: barrier},
+					},
+					timeout: time.Second,
+				}
+			}(),
+			want: []song.Song{newSong("s0:1", "a"), newSong("s1:2", "b")},
+		},
+		{
+			name: "err  service failed",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("1", "a")}},
+					&fakeService{err: errors.New("unavailable")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  service timed out",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("1", "a")}},
+					&fakeService{songs: []song.Song{newSong("2", "b")}, after: make(chan struct{})},
+				},
+				timeout: 20 * time.Millisecond,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(newSources(tt.fields.services...), WithTimeout(tt.fields.timeout))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := a.GetSongs(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Aggregator.GetSongs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Aggregator.GetSongs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregator_GetSongs_Policy(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		failed      int
+		wantCount   int
+		wantSources []string
+		wantErr     bool
+	}{
+		{
+			name:      "ok  strict",
+			policy:    PolicyStrict,
+			wantCount: 3,
+		},
+		{
+			name:    "err  strict",
+			policy:  PolicyStrict,
+			failed:  1,
+			wantErr: true,
+		},
+		{
+			name:        "ok  best effort",
+			policy:      PolicyBestEffort,
+			failed:      2,
+			wantCount:   1,
+			wantSources: []string{"s0", "s1"},
+		},
+		{
+			name:    "err  best effort",
+			policy:  PolicyBestEffort,
+			failed:  3,
+			wantErr: true,
+		},
+		{
+			name:        "ok  quorum",
+			policy:      PolicyQuorum(2),
+			failed:      1,
+			wantCount:   2,
+			wantSources: []string{"s0"},
+		},
+		{
+			name:    "err  quorum",
+			policy:  PolicyQuorum(2),
+			failed:  2,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs := make([]scraper.Service, 0, 3)
+			for i := 0; i < 3; i++ {
+				svc := &fakeService{songs: []song.Song{newSong(fmt.Sprint(i), fmt.Sprint(i))}}
+				if i < tt.failed {
+					svc.err = errUnavailable
+				}
+				svcs = append(svcs, svc)
+			}
+
+			a, err := New(newSources(svcs...), WithPolicy(tt.policy))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := a.GetSongs(context.Background())
+			if tt.wantErr {
+				if aerr := new(AggregationError); !errors.As(err, &aerr) {
+					t.Errorf("Aggregator.GetSongs() error = %v, want *AggregationError", err)
+				}
+				return
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("Aggregator.GetSongs() count = %d, want %d", len(got), tt.wantCount)
+			}
+
+			var sources []string
+			if perr := new(scraper.PartialResultError); errors.As(err, &perr) {
+				for _, src := range perr.Sources {
+					if !errors.Is(src, errUnavailable) {
+						t.Errorf("Aggregator.GetSongs() source error = %v, want %v", src, errUnavailable)
+					}
+					sources = append(sources, src.Source)
+				}
+			} else if err != nil {
+				t.Fatalf("Aggregator.GetSongs() error = %v", err)
+			}
+			if !reflect.DeepEqual(sources, tt.wantSources) {
+				t.Errorf("Aggregator.GetSongs() failed sources = %v, want %v", sources, tt.wantSources)
+			}
+		})
+	}
+}
+
+func TestAggregator_GetSongs_Deterministic(t *testing.T) {
+	ss := []song.Song{newSong("1", "a"), newSong("2", "a"), newSong("3", "a")}
+	want := []song.Song{newSong("s0:1", "a"), newSong("s1:2", "a"), newSong("s2:3", "a")}
+
+	orders := [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	for _, order := range orders {
+		svcs := []*fakeService{
+			{songs: ss[:1]},
+			{songs: ss[1:2]},
+			{songs: ss[2:]},
+		}
+		inOrder(svcs[order[0]], svcs[order[1]], svcs[order[2]])
+
+		a, err := New(newSources(svcs[0], svcs[1], svcs[2]))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		got, err := a.GetSongs(context.Background())
+		if err != nil {
+			t.Fatalf("Aggregator.GetSongs() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Aggregator.GetSongs() completed in order %v = %v, want %v", order, got, want)
+		}
+	}
+}
+
+func TestAggregator_StreamSongs(t *testing.T) {
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name    string
+		fnErr   error
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "ok",
+			want: []string{"s0:1", "s0:2", "s1:3"},
+		},
+		{
+			name:    "err  fn failed",
+			fnErr:   errStop,
+			want:    []string{"1"},
+			wantErr: errStop,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barrier := newBarrier(2)
+			a, err := New(newSources(
+				&fakeService{songs: []song.Song{newSong("1", "a"), newSong("2", "b")}, barrier: barrier},
+				&fakeService{songs: []song.Song{newSong("3", "c")}, barrier: barrier},
+			), WithTimeout(time.Second))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got := make([]string, 0)
+			err = a.StreamSongs(context.Background(), func(s song.Song) error {
+				got = append(got, s.ID) // hint: fn is never called concurrently
+
+				return tt.fnErr
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Aggregator.StreamSongs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.fnErr != nil {
+				if len(got) != 1 {
+					t.Errorf("Aggregator.StreamSongs() called fn %d times after an error, want 1", len(got))
+				}
+				return
+			}
+
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Aggregator.StreamSongs() streamed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregator_StreamSongs_Policy(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		failed      int
+		wantCount   int
+		wantSources []string
+		wantCauses  []string
+	}{
+		{
+			name:       "err  strict cancels the rest",
+			policy:     PolicyStrict,
+			failed:     1,
+			wantCauses: []string{"s0"},
+		},
+		{
+			name:       "err  quorum cancels the rest",
+			policy:     PolicyQuorum(2),
+			failed:     2,
+			wantCauses: []string{"s0", "s1"},
+		},
+		{
+			name:        "ok  best effort",
+			policy:      PolicyBestEffort,
+			failed:      1,
+			wantCount:   2,
+			wantSources: []string{"s0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcs := make([]*fakeService, 0, 3)
+			for i := 0; i < 3; i++ {
+				svc := &fakeService{songs: []song.Song{newSong(fmt.Sprint(i), fmt.Sprint(i))}}
+				if i < tt.failed {
+					svc.err = errUnavailable
+				}
+				svcs = append(svcs, svc)
+			}
+			inOrder(svcs...)
+			if tt.wantCauses != nil {
+				svcs[tt.failed].after = make(chan struct{}) // hint: the rest succeed only if not cancelled
+			}
+
+			a, err := New(newSources(svcs[0], svcs[1], svcs[2]), WithPolicy(tt.policy))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			count := 0
+			err = a.StreamSongs(ctx, func(s song.Song) error {
+				count++
+				return nil
+			})
+			if tt.wantCauses != nil {
+				aerr := new(AggregationError)
+				if !errors.As(err, &aerr) {
+					t.Fatalf("Aggregator.StreamSongs() error = %v, want *AggregationError", err)
+				}
+
+				causes := make([]string, 0)
+				for _, cause := range aerr.Unwrap() {
+					var serr *scraper.SourceError
+					if !errors.As(cause, &serr) || !errors.Is(serr, errUnavailable) {
+						t.Errorf("Aggregator.StreamSongs() cause = %v, want %v", cause, errUnavailable)
+						continue
+					}
+					causes = append(causes, serr.Source)
+				}
+				if !reflect.DeepEqual(causes, tt.wantCauses) {
+					t.Errorf("Aggregator.StreamSongs() causes = %v, want %v", causes, tt.wantCauses)
+				}
+				return
+			}
+
+			var sources []string
+			if perr := new(scraper.PartialResultError); errors.As(err, &perr) {
+				for _, src := range perr.Sources {
+					sources = append(sources, src.Source)
+				}
+			} else if err != nil {
+				t.Fatalf("Aggregator.StreamSongs() error = %v", err)
+			}
+			if !reflect.DeepEqual(sources, tt.wantSources) {
+				t.Errorf("Aggregator.StreamSongs() failed sources = %v, want %v", sources, tt.wantSources)
+			}
+			if count != tt.wantCount {
+				t.Errorf("Aggregator.StreamSongs() count = %d, want %d", count, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestAggregator_GetPreviews(t *testing.T) {
+	type fields struct {
+		services []scraper.Service
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    []song.Metadata
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: func() fields {
+				slow := &fakeService{songs: []song.Song{newSong("2", "b")}}
+				fast := &fakeService{songs: []song.Song{newSong("1", "a")}}
+				inOrder(fast, slow)
+
+				return fields{
+					services: []scraper.Service{slow, fast},
+				}
+			}(),
+			want: []song.Metadata{{ID: "s1:1", Title: "a"}, {ID: "s0:2", Title: "b"}},
+		},
+		{
+			name: "err  service failed",
+			fields: fields{
+				services: []scraper.Service{
+					&fakeService{songs: []song.Song{newSong("1", "a")}},
+					&fakeService{err: errors.New("unavailable")},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(newSources(tt.fields.services...))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := a.GetPreviews(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Aggregator.GetPreviews() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Aggregator.GetPreviews() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
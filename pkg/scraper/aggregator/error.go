@@ -1,10 +1,13 @@
 package aggregator
 
 import (
-	"fmt"
+	"errors"
+	"strings"
 )
 
 // AggregationError is an error with multiple causes and a general message.
+//
+// The causes of the failed sources are [*scraper.SourceError] instances.
 type AggregationError struct {
 	msg    string
 	causes []error
@@ -20,5 +23,41 @@ func NewAggregationError(msg string, causes ...error) *AggregationError {
 
 // Error returns an aggregated error message.
 func (err *AggregationError) Error() string {
-	return fmt.Sprintf("%s: %s", err.msg, err.causes)
+	msgs := make([]string, 0, len(err.causes))
+	for _, cause := range err.causes {
+		msgs = append(msgs, cause.Error())
+	}
+
+	return err.msg + ": [" + strings.Join(msgs, "; ") + "]"
+}
+
+// Unwrap returns the causes of the error.
+func (err *AggregationError) Unwrap() []error {
+	return err.causes
+}
+
+// Is reports whether any cause of the error matches the target.
+//
+// hint: errors.Is doesn't traverse Unwrap() []error before go1.20
+func (err *AggregationError) Is(target error) bool {
+	for _, cause := range err.causes {
+		if errors.Is(cause, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first cause of the error matching the target and sets the target to it.
+//
+// hint: errors.As doesn't traverse Unwrap() []error before go1.20
+func (err *AggregationError) As(target any) bool {
+	for _, cause := range err.causes {
+		if errors.As(cause, target) {
+			return true
+		}
+	}
+
+	return false
 }
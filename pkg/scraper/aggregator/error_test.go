@@ -0,0 +1,41 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
+)
+
+func TestAggregationError(t *testing.T) {
+	timeout := &scraper.SourceError{Source: "a", Err: context.DeadlineExceeded}
+	boom := &scraper.SourceError{Source: "b", Err: errors.New("boom")}
+	err := fmt.Errorf("failed to get songs: %w", NewAggregationError("failed", timeout, boom))
+
+	if want := "failed to get songs: failed: [" + timeout.Error() + "; " + boom.Error() + "]"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("errors.Is(%v) = false, want true", context.DeadlineExceeded)
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Errorf("errors.Is(%v) = true, want false", context.Canceled)
+	}
+
+	var serr *scraper.SourceError
+	if !errors.As(err, &serr) || serr != timeout {
+		t.Errorf("errors.As() = %v, want %v", serr, timeout)
+	}
+
+	var aerr *AggregationError
+	if !errors.As(err, &aerr) {
+		t.Fatalf("errors.As() = false, want true")
+	}
+	if want := []error{timeout, boom}; !reflect.DeepEqual(aerr.Unwrap(), want) {
+		t.Errorf("Unwrap() = %v, want %v", aerr.Unwrap(), want)
+	}
+}
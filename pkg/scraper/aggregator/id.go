@@ -0,0 +1,47 @@
+package aggregator
+
+import (
+	"strings"
+
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
+)
+
+// IDSeparator separates the source name and the source id of a song, e.g. "grob:123".
+const IDSeparator = ":"
+
+// QualifyID returns the id of a song qualified with the source name.
+func QualifyID(source, id string) string {
+	return source + IDSeparator + id
+}
+
+// SplitID splits the qualified id of a song into the source name and the source id,
+// ok is false if the id doesn't contain the [IDSeparator].
+// The source name is not checked, an id of a source may contain the separator itself.
+func SplitID(id string) (source, sourceID string, ok bool) {
+	return strings.Cut(id, IDSeparator)
+}
+
+// qualifySongs returns copies of the songs with the ids qualified with the source name.
+func qualifySongs(source string, ss []song.Song) []song.Song {
+	res := make([]song.Song, 0, len(ss))
+	for _, s := range ss {
+		s.ID = QualifyID(source, s.ID)
+		res = append(res, s)
+	}
+
+	return res
+}
+
+// qualifyFailures returns copies of the failures with the ids qualified with the source name.
+func qualifyFailures(source string, failures []*scraper.SongError) []*scraper.SongError {
+	res := make([]*scraper.SongError, 0, len(failures))
+	for _, f := range failures {
+		f := *f
+		f.ID = QualifyID(source, f.ID)
+		res = append(res, &f)
+	}
+
+	return res
+}
@@ -0,0 +1,195 @@
+package aggregator
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+)
+
+// SourceSongs is a list of songs scraped from a single source.
+type SourceSongs struct {
+	Source string
+	Songs  []song.Song
+}
+
+// MergeStrategy merges songs of multiple sources into a single list.
+//
+// Sources are passed in the order of the [Aggregator] sources,
+// the result is sorted by the [Aggregator] afterwards.
+type MergeStrategy interface {
+	Merge(sources []SourceSongs) []song.Song
+}
+
+// MergeFunc is an adapter to allow the use of ordinary functions as a [MergeStrategy].
+type MergeFunc func(sources []SourceSongs) []song.Song
+
+// Merge calls f(sources).
+func (f MergeFunc) Merge(sources []SourceSongs) []song.Song {
+	return f(sources)
+}
+
+// Concat is a [MergeStrategy] concatenating songs of all sources as is.
+var Concat MergeStrategy = MergeFunc(func(sources []SourceSongs) []song.Song {
+	res := make([]song.Song, 0)
+	for _, src := range sources {
+		res = append(res, src.Songs...)
+	}
+
+	return res
+})
+
+const (
+	// TagAuthor is the name of the song tag with the author.
+	TagAuthor = "author"
+	// TagAlbum is the name of the song tag with the album.
+	TagAlbum = "album"
+	// TagSource is the name of the song tag with the source the song is scraped from.
+	TagSource = "source"
+)
+
+// Dedup is a [MergeStrategy] merging the same song scraped from different sources.
+//
+// Songs are considered the same if their normalized titles, authors and albums are equal,
+// songs of a single source are never merged with each other.
+// The merged song takes the id, title and lyrics of the source with the highest priority,
+// tags of all the songs, and a [TagSource] tag for each contributing source.
+type Dedup struct {
+	priority map[string]int
+}
+
+// NewDedup returns a pointer to the new instance of [Dedup].
+//
+// Sources are prioritized in the order of the names,
+// sources not listed have the lowest priority and keep the order of the [Aggregator] sources.
+func NewDedup(priority ...string) *Dedup {
+	d := &Dedup{
+		priority: make(map[string]int, len(priority)),
+	}
+	for i, name := range priority {
+		if _, ok := d.priority[name]; !ok {
+			d.priority[name] = i
+		}
+	}
+
+	return d
+}
+
+// Merge merges the same songs of the sources.
+func (d *Dedup) Merge(sources []SourceSongs) []song.Song {
+	type group struct {
+		songs   []song.Song
+		sources []string
+	}
+
+	groups := make([]*group, 0)
+	byKey := make(map[string][]*group)
+	for _, src := range d.sorted(sources) {
+		seen := make(map[*group]bool) // hint: songs of a single source are not merged
+		for _, s := range src.Songs {
+			key := songKey(s)
+
+			var g *group
+			for _, candidate := range byKey[key] {
+				if !seen[candidate] {
+					g = candidate
+					break
+				}
+			}
+
+			if g == nil {
+				g = new(group)
+				groups = append(groups, g)
+				byKey[key] = append(byKey[key], g)
+			}
+
+			seen[g] = true
+			g.songs = append(g.songs, s)
+			g.sources = append(g.sources, src.Source)
+		}
+	}
+
+	res := make([]song.Song, 0, len(groups))
+	for _, g := range groups {
+		s := g.songs[0] // hint: the song of the source with the highest priority
+
+		tags := make(song.Tags, 0)
+		seen := make(map[song.Tag]bool)
+		for _, gs := range g.songs {
+			for _, t := range gs.Tags {
+				if !seen[t] {
+					seen[t] = true
+					tags = append(tags, t)
+				}
+			}
+		}
+
+		for _, name := range g.sources {
+			t := song.Tag{Name: TagSource, Value: name}
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+
+		s.Tags = tags
+		res = append(res, s)
+	}
+
+	return res
+}
+
+// sorted returns the sources ordered by priority.
+func (d *Dedup) sorted(sources []SourceSongs) []SourceSongs {
+	rank := func(name string) int {
+		if p, ok := d.priority[name]; ok {
+			return p
+		}
+
+		return math.MaxInt
+	}
+
+	res := append([]SourceSongs(nil), sources...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return rank(res[i].Source) < rank(res[j].Source)
+	})
+
+	return res
+}
+
+// songKey returns the key identifying the song across sources.
+func songKey(s song.Song) string {
+	var author, album string
+	for _, t := range s.Tags {
+		switch t.Name {
+		case TagAuthor:
+			if author == "" {
+				author = t.Value
+			}
+		case TagAlbum:
+			if album == "" {
+				album = t.Value
+			}
+		}
+	}
+
+	return strings.Join([]string{normalize(s.Title), normalize(author), normalize(album)}, "\x00")
+}
+
+// normalize folds the case and the letter "ё", drops punctuation and collapses spaces.
+func normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == 'ё' || r == 'Ё':
+			return 'е'
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		default:
+			return ' '
+		}
+	}, s)
+
+	return strings.Join(strings.Fields(s), " ")
+}
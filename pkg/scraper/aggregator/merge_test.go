@@ -0,0 +1,112 @@
+package aggregator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+)
+
+func TestConcat_Merge(t *testing.T) {
+	sources := []SourceSongs{
+		{Source: "a", Songs: []song.Song{newSong("1", "x")}},
+		{Source: "b", Songs: []song.Song{newSong("1", "x"), newSong("2", "y")}},
+	}
+	want := []song.Song{newSong("1", "x"), newSong("1", "x"), newSong("2", "y")}
+
+	if got := Concat.Merge(sources); !reflect.DeepEqual(got, want) {
+		t.Errorf("Concat.Merge() = %v, want %v", got, want)
+	}
+}
+
+func TestDedup_Merge(t *testing.T) {
+	newTaggedSong := func(id, title string, lyrics string, tags ...song.Tag) song.Song {
+		s := newSong(id, title)
+		s.Tags = tags
+		s.Lyrics = song.Lyrics{{Quotes: []song.Quote{{Phrase: lyrics}}}}
+
+		return s
+	}
+	author := song.Tag{Name: TagAuthor, Value: "Е. Летов"}
+	album := song.Tag{Name: TagAlbum, Value: "Всё идёт по плану"}
+	artist := song.Tag{Name: "artist", Value: "Гражданская Оборона"}
+
+	type fields struct {
+		priority []string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		sources []SourceSongs
+		want    []song.Song
+	}{
+		{
+			name: "ok  same song merged",
+			sources: []SourceSongs{
+				{Source: "a", Songs: []song.Song{newTaggedSong("1", "Всё идёт по плану", "a", author, album)}},
+				{Source: "b", Songs: []song.Song{newTaggedSong("7", "всё идёт по плану!", "b", author, artist, album)}},
+			},
+			want: []song.Song{
+				newTaggedSong("1", "Всё идёт по плану", "a",
+					author, album, artist,
+					song.Tag{Name: TagSource, Value: "a"},
+					song.Tag{Name: TagSource, Value: "b"},
+				),
+			},
+		},
+		{
+			name: "ok  canonical song by priority",
+			fields: fields{
+				priority: []string{"b"},
+			},
+			sources: []SourceSongs{
+				{Source: "a", Songs: []song.Song{newTaggedSong("1", "Всё идёт по плану", "a", author, album)}},
+				{Source: "b", Songs: []song.Song{newTaggedSong("7", "Все идет по плану", "b", author, album)}},
+			},
+			want: []song.Song{
+				newTaggedSong("7", "Все идет по плану", "b",
+					author, album,
+					song.Tag{Name: TagSource, Value: "b"},
+					song.Tag{Name: TagSource, Value: "a"},
+				),
+			},
+		},
+		{
+			name: "ok  different albums are not merged",
+			sources: []SourceSongs{
+				{Source: "a", Songs: []song.Song{newTaggedSong("1", "Всё идёт по плану", "a", author, album)}},
+				{Source: "b", Songs: []song.Song{newTaggedSong("7", "Всё идёт по плану", "b", author)}},
+			},
+			want: []song.Song{
+				newTaggedSong("1", "Всё идёт по плану", "a", author, album, song.Tag{Name: TagSource, Value: "a"}),
+				newTaggedSong("7", "Всё идёт по плану", "b", author, song.Tag{Name: TagSource, Value: "b"}),
+			},
+		},
+		{
+			name: "ok  songs of a single source are not merged",
+			sources: []SourceSongs{
+				{Source: "a", Songs: []song.Song{
+					newTaggedSong("1", "Всё идёт по плану", "a", author),
+					newTaggedSong("2", "Всё идёт по плану", "a", author),
+				}},
+				{Source: "b", Songs: []song.Song{newTaggedSong("7", "Всё идёт по плану", "b", author)}},
+			},
+			want: []song.Song{
+				newTaggedSong("1", "Всё идёт по плану", "a",
+					author,
+					song.Tag{Name: TagSource, Value: "a"},
+					song.Tag{Name: TagSource, Value: "b"},
+				),
+				newTaggedSong("2", "Всё идёт по плану", "a", author, song.Tag{Name: TagSource, Value: "a"}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDedup(tt.fields.priority...)
+			if got := d.Merge(tt.sources); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dedup.Merge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package aggregator
+
+import (
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
+)
+
+// Policy is a policy of tolerating failures of the sources.
+//
+// Failures of the tolerated sources are returned along with the results of the succeeded ones
+// as a [*scraper.PartialResultError].
+type Policy struct {
+	// Quorum is the minimum number of sources required to succeed, zero requires all the sources.
+	Quorum int
+}
+
+var (
+	// PolicyStrict requires all the sources to succeed.
+	PolicyStrict = Policy{}
+	// PolicyBestEffort requires at least one source to succeed.
+	PolicyBestEffort = Policy{Quorum: 1}
+)
+
+// PolicyQuorum requires at least n sources to succeed.
+func PolicyQuorum(n int) Policy {
+	return Policy{Quorum: n}
+}
+
+// satisfied reports whether the number of succeeded sources out of total satisfies the policy.
+func (p Policy) satisfied(succeeded, total int) bool {
+	if p.Quorum == 0 {
+		return succeeded == total
+	}
+
+	return succeeded >= p.Quorum
+}
+
+// apply returns an [*AggregationError] if the failures of the sources don't satisfy the policy.
+func (a *Aggregator) apply(msg string, failed []*scraper.SourceError) error {
+	if a.policy.satisfied(len(a.sources)-len(failed), len(a.sources)) {
+		return nil
+	}
+
+	causes := make([]error, 0, len(failed))
+	for _, err := range failed {
+		causes = append(causes, err)
+	}
+
+	return NewAggregationError(msg, causes...)
+}
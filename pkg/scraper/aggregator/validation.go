@@ -0,0 +1,60 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdkerrors "github.com/linden-honey/linden-honey-sdk-go/errors"
+)
+
+// Validate validates an [Aggregator] and returns an error if validation is failed.
+func (a Aggregator) Validate() error {
+	if len(a.sources) == 0 {
+		return sdkerrors.NewInvalidValueError("sources", sdkerrors.ErrEmptyValue)
+	}
+
+	names := make(map[string]struct{}, len(a.sources))
+	for i, src := range a.sources {
+		if err := src.Validate(); err != nil {
+			return sdkerrors.NewInvalidValueError(fmt.Sprintf("sources[%d]", i), err)
+		}
+
+		if _, ok := names[src.Name]; ok {
+			return sdkerrors.NewInvalidValueError(fmt.Sprintf("sources[%d]", i), fmt.Errorf("duplicate source name %q", src.Name))
+		}
+
+		names[src.Name] = struct{}{}
+	}
+
+	if a.timeout < 0 {
+		return sdkerrors.NewInvalidValueError("timeout", errors.New("should be greater than or equal to zero"))
+	}
+
+	if a.policy.Quorum < 0 || a.policy.Quorum > len(a.sources) {
+		return sdkerrors.NewInvalidValueError("policy", errors.New("quorum should be between zero and the number of sources"))
+	}
+
+	if a.merge == nil {
+		return sdkerrors.NewRequiredValueError("merge")
+	}
+
+	return nil
+}
+
+// Validate validates a [Source] and returns an error if validation is failed.
+func (src Source) Validate() error {
+	if strings.TrimSpace(src.Name) == "" {
+		return sdkerrors.NewInvalidValueError("name", sdkerrors.ErrEmptyValue)
+	}
+
+	if strings.Contains(src.Name, IDSeparator) {
+		return sdkerrors.NewInvalidValueError("name", fmt.Errorf("should not contain %q", IDSeparator))
+	}
+
+	if src.Service == nil {
+		return sdkerrors.NewRequiredValueError("service")
+	}
+
+	return nil
+}
@@ -10,5 +10,6 @@ import (
 type Service interface {
 	GetSong(ctx context.Context, id string) (*song.Song, error)
 	GetSongs(ctx context.Context) ([]song.Song, error)
+	StreamSongs(ctx context.Context, fn func(s song.Song) error) error
 	GetPreviews(ctx context.Context) ([]song.Metadata, error)
 }
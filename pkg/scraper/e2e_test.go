@@ -0,0 +1,148 @@
+package scraper_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/fetcher"
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/parser"
+)
+
+type songsResponse struct {
+	Songs    []song.Song   `json:"songs"`
+	Failures []songFailure `json:"failures"`
+}
+
+type songFailure struct {
+	ID    string        `json:"id"`
+	Stage scraper.Stage `json:"stage"`
+	Error string        `json:"error"`
+}
+
+// newReplayHandler returns the HTTP handler of the [scraper.Scraper] serving the recorded fixtures of the source.
+func newReplayHandler(t *testing.T, dir string) http.Handler {
+	t.Helper()
+
+	f, err := fetcher.NewReplayer(dir)
+	if err != nil {
+		t.Fatalf("fetcher.NewReplayer() error = %v", err)
+	}
+
+	scr, err := scraper.New(f, parser.NewGrobParser(), scraper.WithValidation(true), scraper.WithPartialResults(true))
+	if err != nil {
+		t.Fatalf("scraper.New() error = %v", err)
+	}
+
+	return scraper.NewHTTPHandler(scr)
+}
+
+// TestE2E_Grob runs the scraper against the synthetic pages in the markup of the source,
+// see testdata/README.md for capturing the real pages.
+func TestE2E_Grob(t *testing.T) {
+	h := newReplayHandler(t, "testdata/grob_synthetic")
+
+	song1 := song.Song{
+		Metadata: song.Metadata{
+			ID:    "1",
+			Title: "Всё идёт по плану",
+			Tags: song.Tags{
+				{Name: "author", Value: "Е. Летов"},
+				{Name: "artist", Value: "Гражданская Оборона"},
+				{Name: "album", Value: "Всё идёт по плану"},
+			},
+		},
+		Lyrics: song.Lyrics{
+			{Quotes: []song.Quote{{Phrase: "Первая строка первого куплета"}, {Phrase: "Вторая строка первого куплета"}}},
+			{Quotes: []song.Quote{{Phrase: "Первая строка припева"}, {Phrase: "Вторая строка припева"}}},
+		},
+	}
+	song2 := song.Song{
+		Metadata: song.Metadata{
+			ID:    "2",
+			Title: "Моя оборона",
+			Tags: song.Tags{
+				{Name: "author", Value: "Е. Летов"},
+				{Name: "artist", Value: "Гражданская Оборона"},
+				{Name: "album", Value: "Армагеддон-попс"},
+			},
+		},
+		Lyrics: song.Lyrics{
+			{Quotes: []song.Quote{{Phrase: "Первая строка"}, {Phrase: "Вторая строка"}}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		got        interface{}
+		want       interface{}
+	}{
+		{
+			name:       "ok  song",
+			target:     "/1",
+			wantStatus: http.StatusOK,
+			got:        new(song.Song),
+			want:       &song1,
+		},
+		{
+			name:       "ok  previews",
+			target:     "/previews",
+			wantStatus: http.StatusOK,
+			got:        new([]song.Metadata),
+			want: &[]song.Metadata{
+				{ID: "1", Title: "Всё идёт по плану"},
+				{ID: "2", Title: "Моя оборона"},
+				{ID: "3", Title: "Пластилин"},
+			},
+		},
+		{
+			name:       "ok  songs with failures",
+			target:     "/?failures=true",
+			wantStatus: http.StatusOK,
+			got:        new(songsResponse),
+			want: &songsResponse{
+				Songs: []song.Song{song1, song2},
+				Failures: []songFailure{
+					{
+						ID:    "3",
+						Stage: scraper.StageFetch,
+						Error: "failed to fetch data: server did not respond successfully - status code 404",
+					},
+				},
+			},
+		},
+		{
+			name:       "err  song not found",
+			target:     "/3",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			if tt.got == nil {
+				return
+			}
+
+			if err := json.Unmarshal(rec.Body.Bytes(), tt.got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("body = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
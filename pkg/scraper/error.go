@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is an error returned when a song is not found.
+//
+// Errors of other packages, e.g. of a fetcher, report a missing song with the NotFound() bool method.
+var ErrNotFound = errors.New("song not found")
+
+// Stage is a stage of scraping a song.
+type Stage string
+
+const (
+	// StageFetch is a stage of fetching a song content.
+	StageFetch Stage = "fetch"
+	// StageParse is a stage of parsing a song content.
+	StageParse Stage = "parse"
+	// StageValidate is a stage of validating a parsed song.
+	StageValidate Stage = "validate"
+)
+
+// SongError is an error happened on some stage of scraping a song by id.
+type SongError struct {
+	ID    string
+	Stage Stage
+	Err   error
+}
+
+// Error returns an error message.
+func (err *SongError) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (err *SongError) Unwrap() error {
+	return err.Err
+}
+
+// SourceError is an error of a single source of aggregated results.
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+// Error returns an error message with the source name.
+func (err *SourceError) Error() string {
+	return fmt.Sprintf("failed to scrape the %s source: %s", err.Source, err.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (err *SourceError) Unwrap() error {
+	return err.Err
+}
+
+// PartialResultError is an error returned along with successfully scraped songs
+// when some songs or whole sources failed to be scraped.
+type PartialResultError struct {
+	Failures []*SongError
+	// Sources are the failed sources tolerated by the aggregation policy.
+	Sources []*SourceError
+}
+
+// Error returns an error message with the number of failures.
+func (err *PartialResultError) Error() string {
+	if len(err.Sources) != 0 {
+		return fmt.Sprintf("failed to get %d song(s) and %d source(s)", len(err.Failures), len(err.Sources))
+	}
+
+	return fmt.Sprintf("failed to get %d song(s)", len(err.Failures))
+}
@@ -0,0 +1,132 @@
+package fetcher
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// BackoffStrategy is a strategy of computing delays between retry attempts.
+type BackoffStrategy string
+
+const (
+	// BackoffConstant waits MinInterval between attempts.
+	BackoffConstant BackoffStrategy = "constant"
+	// BackoffLinear waits MinInterval increased by Factor on each attempt.
+	BackoffLinear BackoffStrategy = "linear"
+	// BackoffExponential waits a random delay up to Factor doubled on each attempt ("full jitter").
+	BackoffExponential BackoffStrategy = "exponential"
+	// BackoffDecorrelatedJitter waits a random delay between MinInterval and the tripled previous delay.
+	BackoffDecorrelatedJitter BackoffStrategy = "decorrelated_jitter"
+)
+
+// Backoff computes delays between retry attempts of a single fetch.
+type Backoff interface {
+	// Next returns the delay before the next attempt, the attempt starts from 0.
+	Next(attempt int) time.Duration
+}
+
+// Rand is a source of random numbers.
+type Rand interface {
+	// Int63n returns a non-negative pseudo-random number in [0,n).
+	Int63n(n int64) int64
+}
+
+// Clock is a source of time.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// NewBackoff returns a new instance of [Backoff] for the strategy of the [RetryConfig].
+// All delays are clamped to the [MinInterval, MaxInterval] range.
+func (cfg RetryConfig) NewBackoff(rnd Rand) Backoff {
+	return &backoff{
+		cfg: cfg,
+		rnd: rnd,
+	}
+}
+
+type backoff struct {
+	cfg  RetryConfig
+	rnd  Rand
+	prev time.Duration
+}
+
+// Next returns the delay before the next attempt.
+func (b *backoff) Next(attempt int) time.Duration {
+	var delay time.Duration
+	switch b.cfg.Strategy {
+	case BackoffConstant:
+		delay = b.cfg.MinInterval + b.jitter(b.cfg.MaxJitterInterval)
+	case BackoffLinear:
+		delay = b.cfg.MinInterval + b.cfg.Factor*time.Duration(attempt) + b.jitter(b.cfg.MaxJitterInterval)
+	case BackoffDecorrelatedJitter:
+		prev := b.prev
+		if prev < b.cfg.MinInterval {
+			prev = b.cfg.MinInterval
+		}
+		upper := prev * 3
+		if upper > b.cfg.MaxInterval {
+			upper = b.cfg.MaxInterval
+		}
+		delay = b.cfg.MinInterval + b.jitter(upper-b.cfg.MinInterval)
+	default: // hint: exponential is the default strategy
+		upper := b.cfg.MaxInterval
+		if attempt < 62 && b.cfg.Factor <= upper>>uint(attempt) {
+			upper = b.cfg.Factor << uint(attempt)
+		}
+		delay = b.jitter(upper)
+	}
+
+	if delay < b.cfg.MinInterval {
+		delay = b.cfg.MinInterval
+	}
+	if delay > b.cfg.MaxInterval {
+		delay = b.cfg.MaxInterval
+	}
+	b.prev = delay
+
+	return delay
+}
+
+// jitter returns a random duration in [0,max].
+func (b *backoff) jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	return time.Duration(b.rnd.Int63n(int64(max) + 1))
+}
+
+// lockedRand is a [Rand] safe for concurrent use.
+type lockedRand struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{
+		rnd: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Int63n returns a non-negative pseudo-random number in [0,n).
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rnd.Int63n(n)
+}
+
+type realClock struct{}
+
+// Now returns the current local time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the current time on the returned channel.
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
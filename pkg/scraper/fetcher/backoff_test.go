@@ -0,0 +1,140 @@
+package fetcher
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRand returns the fraction of the upper bound.
+type fakeRand struct {
+	fraction float64
+}
+
+func (r fakeRand) Int63n(n int64) int64 {
+	return int64(float64(n-1) * r.fraction)
+}
+
+// fakeClock advances the time by the requested duration instantly and records the durations.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.delays = append(c.delays, d)
+	c.now = c.now.Add(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+
+	return ch
+}
+
+func TestRetryConfig_NewBackoff(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RetryConfig
+		rnd  Rand
+		want []time.Duration
+	}{
+		{
+			name: "constant",
+			cfg: RetryConfig{
+				Strategy:    BackoffConstant,
+				MinInterval: time.Second,
+				MaxInterval: 10 * time.Second,
+			},
+			rnd:  fakeRand{fraction: 1},
+			want: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name: "constant with jitter",
+			cfg: RetryConfig{
+				Strategy:          BackoffConstant,
+				MinInterval:       time.Second,
+				MaxInterval:       10 * time.Second,
+				MaxJitterInterval: 500 * time.Millisecond,
+			},
+			rnd:  fakeRand{fraction: 0.5},
+			want: []time.Duration{1250 * time.Millisecond, 1250 * time.Millisecond, 1250 * time.Millisecond},
+		},
+		{
+			name: "linear",
+			cfg: RetryConfig{
+				Strategy:    BackoffLinear,
+				MinInterval: time.Second,
+				MaxInterval: 4 * time.Second,
+				Factor:      time.Second,
+			},
+			rnd:  fakeRand{fraction: 1},
+			want: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second, 4 * time.Second},
+		},
+		{
+			name: "exponential upper bound",
+			cfg: RetryConfig{
+				Strategy:    BackoffExponential,
+				MinInterval: time.Second,
+				MaxInterval: 10 * time.Second,
+				Factor:      time.Second,
+			},
+			rnd:  fakeRand{fraction: 1},
+			want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second},
+		},
+		{
+			name: "exponential full jitter",
+			cfg: RetryConfig{
+				Strategy:    BackoffExponential,
+				MinInterval: time.Second,
+				MaxInterval: 10 * time.Second,
+				Factor:      2 * time.Second,
+			},
+			rnd:  fakeRand{fraction: 0.5},
+			want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second},
+		},
+		{
+			name: "exponential by default",
+			cfg: RetryConfig{
+				MinInterval: time.Second,
+				MaxInterval: time.Minute,
+				Factor:      time.Second,
+			},
+			rnd:  fakeRand{fraction: 1},
+			want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name: "decorrelated jitter",
+			cfg: RetryConfig{
+				Strategy:    BackoffDecorrelatedJitter,
+				MinInterval: time.Second,
+				MaxInterval: 20 * time.Second,
+			},
+			rnd:  fakeRand{fraction: 1},
+			want: []time.Duration{3 * time.Second, 9 * time.Second, 20 * time.Second, 20 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.cfg.NewBackoff(tt.rnd)
+			got := make([]time.Duration, 0)
+			for attempt := range tt.want {
+				got = append(got, b.Next(attempt))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Backoff.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
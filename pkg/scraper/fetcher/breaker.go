@@ -0,0 +1,157 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState is a state of the [CircuitBreaker].
+type CircuitState string
+
+const (
+	// CircuitClosed passes all requests, failures are counted.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects all requests until the cooldown is passed.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen passes a single probe request, the result of the probe closes or opens the circuit.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// ErrCircuitOpen is an error returned when the request is rejected by the [CircuitBreaker].
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerConfig is the circuit breaker configuration for [Fetcher].
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive failures opening the circuit.
+	Threshold int
+	// Cooldown is the period of time the circuit stays open before a probe request is passed.
+	Cooldown time.Duration
+	// OnStateChange is called on each state transition, it must not block.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker is a circuit breaker failing requests fast while the source is unhealthy.
+//
+// Failures are the errors accepted by the retry policy of the [Fetcher],
+// each attempt of a retried fetch is counted separately.
+type CircuitBreaker struct {
+	cfg   CircuitBreakerConfig
+	clock Clock
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker returns a pointer to the new instance of [CircuitBreaker] or an error.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) (*CircuitBreaker, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &CircuitBreaker{
+		cfg:   cfg,
+		clock: realClock{},
+		state: CircuitClosed,
+	}, nil
+}
+
+// State returns the current state of the [CircuitBreaker].
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen && cb.cooledDown() {
+		return CircuitHalfOpen // hint: the next request is a probe
+	}
+
+	return cb.state
+}
+
+// allow returns [ErrCircuitOpen] if the request is rejected,
+// otherwise the result of the request must be passed to done.
+func (cb *CircuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if !cb.cooledDown() {
+			return ErrCircuitOpen
+		}
+
+		cb.setState(CircuitHalfOpen)
+		cb.probing = true
+
+		return nil
+	case CircuitHalfOpen:
+		if cb.probing {
+			return ErrCircuitOpen
+		}
+
+		cb.probing = true
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+// done records the result of the allowed request.
+// Cancelled requests are neither failures nor successes.
+func (cb *CircuitBreaker) done(err error, isFailure RetryPolicy) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		if cb.state == CircuitHalfOpen {
+			cb.probing = false // hint: let another request probe the source
+		}
+
+		return
+	}
+
+	failed := err != nil && isFailure(err)
+	switch cb.state {
+	case CircuitClosed:
+		if !failed {
+			cb.failures = 0
+			return
+		}
+
+		cb.failures++
+		if cb.failures >= cb.cfg.Threshold {
+			cb.open()
+		}
+	case CircuitHalfOpen:
+		cb.probing = false
+		if failed {
+			cb.open()
+			return
+		}
+
+		cb.failures = 0
+		cb.setState(CircuitClosed)
+	}
+}
+
+func (cb *CircuitBreaker) open() {
+	cb.openedAt = cb.clock.Now()
+	cb.setState(CircuitOpen)
+}
+
+func (cb *CircuitBreaker) cooledDown() bool {
+	return !cb.clock.Now().Before(cb.openedAt.Add(cb.cfg.Cooldown))
+}
+
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	from := cb.state
+	cb.state = state
+	if from != state && cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(from, state)
+	}
+}
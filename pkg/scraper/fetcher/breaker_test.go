@@ -0,0 +1,120 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var transitions []CircuitState
+	cb, err := NewCircuitBreaker(CircuitBreakerConfig{
+		Threshold: 2,
+		Cooldown:  time.Minute,
+		OnStateChange: func(_, to CircuitState) {
+			transitions = append(transitions, to)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewCircuitBreaker() error = %v", err)
+	}
+
+	clock := &fakeClock{now: time.Now()}
+	cb.clock = clock
+
+	failure := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	notFound := &StatusError{StatusCode: http.StatusNotFound}
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		err       error
+		wantAllow bool
+		wantState CircuitState
+	}{
+		{name: "failure", err: failure, wantAllow: true, wantState: CircuitClosed},
+		{name: "not a failure resets", err: notFound, wantAllow: true, wantState: CircuitClosed},
+		{name: "failure", err: failure, wantAllow: true, wantState: CircuitClosed},
+		{name: "failure opens", err: failure, wantAllow: true, wantState: CircuitOpen},
+		{name: "rejected", wantAllow: false, wantState: CircuitOpen},
+		{name: "failed probe opens", advance: time.Minute, err: failure, wantAllow: true, wantState: CircuitOpen},
+		{name: "cancelled probe", advance: time.Minute, err: context.Canceled, wantAllow: true, wantState: CircuitHalfOpen},
+		{name: "successful probe closes", wantAllow: true, wantState: CircuitClosed},
+	}
+	for _, step := range steps {
+		clock.now = clock.now.Add(step.advance)
+
+		err := cb.allow()
+		if allowed := err == nil; allowed != step.wantAllow {
+			t.Fatalf("%s: CircuitBreaker.allow() error = %v, want allowed %v", step.name, err, step.wantAllow)
+		}
+		if err == nil {
+			cb.done(step.err, DefaultRetryPolicy)
+		}
+
+		if got := cb.State(); got != step.wantState {
+			t.Fatalf("%s: CircuitBreaker.State() = %v, want %v", step.name, got, step.wantState)
+		}
+	}
+
+	want := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if !reflect.DeepEqual(transitions, want) {
+		t.Errorf("CircuitBreaker transitions = %v, want %v", transitions, want)
+	}
+}
+
+func TestFetcher_Fetch_CircuitBreaker(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cb, err := NewCircuitBreaker(CircuitBreakerConfig{
+		Threshold: 3,
+		Cooldown:  time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewCircuitBreaker() error = %v", err)
+	}
+
+	u, _ := url.Parse(srv.URL)
+	f, err := New(
+		u,
+		charmap.Windows1251,
+		WithRobots(false),
+		WithClock(&fakeClock{now: time.Now()}),
+		WithCircuitBreaker(cb),
+		WithRetry(&RetryConfig{
+			Attempts:    5,
+			MinInterval: time.Second,
+			MaxInterval: 10 * time.Second,
+			Factor:      time.Second,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := f.Fetch(context.Background(), "/"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Fetcher.Fetch() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if _, err := f.Fetch(context.Background(), "/"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Fetcher.Fetch() error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("Fetcher.Fetch() requests = %d, want %d", n, 3)
+	}
+	if got := cb.State(); got != CircuitOpen {
+		t.Errorf("CircuitBreaker.State() = %v, want %v", got, CircuitOpen)
+	}
+}
@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache is a storage of fetched responses keyed by the resolved URL.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, e *CacheEntry) error
+}
+
+// CacheEntry is a cached response.
+type CacheEntry struct {
+	URL       string      `json:"url"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	StoredAt  time.Time   `json:"stored_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// Fresh reports whether the entry can be used without a request to the server.
+func (e CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// Size returns an approximate size of the entry in bytes.
+func (e CacheEntry) Size() int64 {
+	size := int64(len(e.URL) + len(e.Body))
+	for k, vs := range e.Header {
+		for _, v := range vs {
+			size += int64(len(k) + len(v))
+		}
+	}
+
+	return size
+}
+
+// newCacheEntry returns a pointer to the new instance of [CacheEntry]
+// with the expiration time computed from the Cache-Control and Expires headers
+// or from the default ttl if the headers are missing.
+// It returns false if the response must not be stored.
+func newCacheEntry(u string, header http.Header, body []byte, now time.Time, ttl time.Duration) (*CacheEntry, bool) {
+	expiresAt := now.Add(ttl)
+
+	cc := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := cc["no-store"]; ok {
+		return nil, false
+	}
+
+	if v, ok := cc["max-age"]; ok {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			expiresAt = now.Add(time.Duration(seconds) * time.Second)
+		}
+	} else if v := header.Get("Expires"); v != "" {
+		// hint: invalid values like "0" mean already expired
+		t, err := http.ParseTime(v)
+		if err != nil {
+			t = now
+		}
+		expiresAt = t
+	}
+
+	if _, ok := cc["no-cache"]; ok {
+		expiresAt = now
+	}
+
+	return &CacheEntry{
+		URL:       u,
+		Header:    header,
+		Body:      body,
+		StoredAt:  now,
+		ExpiresAt: expiresAt,
+	}, true
+}
+
+func parseCacheControl(v string) map[string]string {
+	cc := make(map[string]string)
+	for _, directive := range strings.Split(v, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(directive, "=")
+		cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	return cc
+}
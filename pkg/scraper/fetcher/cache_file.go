@@ -0,0 +1,142 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileCache is a filesystem implementation of the [Cache]
+// that stores each entry in a separate file and evicts least recently used entries
+// when the max size is exceeded.
+type FileCache struct {
+	mu      sync.Mutex
+	dir     string
+	maxSize int64
+}
+
+// NewFileCache returns a pointer to the new instance of [FileCache]
+// limited by the max size in bytes or an error.
+func NewFileCache(dir string, maxSize int64) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create a cache directory: %w", err)
+	}
+
+	return &FileCache{
+		dir:     dir,
+		maxSize: maxSize,
+	}, nil
+}
+
+// Get returns a cached entry by key.
+func (c *FileCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := c.path(key)
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, false
+	}
+
+	e := new(CacheEntry)
+	if err := json.Unmarshal(data, e); err != nil || e.URL != key {
+		return nil, false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(name, now, now) // mark as recently used
+
+	return e, true
+}
+
+// Set stores an entry by key and evicts least recently used entries if the max size is exceeded.
+func (c *FileCache) Set(key string, e *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode a cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create a cache file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write a cache file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close a cache file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("failed to rename a cache file: %w", err)
+	}
+
+	if err := c.evict(); err != nil {
+		return fmt.Errorf("failed to evict cache entries: %w", err)
+	}
+
+	return nil
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *FileCache) evict() error {
+	des, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read a cache directory: %w", err)
+	}
+
+	fis := make([]os.FileInfo, 0, len(des))
+	size := int64(0)
+	for _, de := range des {
+		if filepath.Ext(de.Name()) != ".json" {
+			continue
+		}
+
+		fi, err := de.Info()
+		if err != nil {
+			continue // removed concurrently
+		}
+
+		fis = append(fis, fi)
+		size += fi.Size()
+	}
+
+	sort.Slice(fis, func(i, j int) bool {
+		return fis[i].ModTime().Before(fis[j].ModTime())
+	})
+
+	for _, fi := range fis {
+		if size <= c.maxSize {
+			break
+		}
+
+		if err := os.Remove(filepath.Join(c.dir, fi.Name())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove a cache file: %w", err)
+		}
+
+		size -= fi.Size()
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package fetcher
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MemoryCache is an in-memory implementation of the [Cache]
+// that evicts least recently used entries when the max size is exceeded.
+type MemoryCache struct {
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns a pointer to the new instance of [MemoryCache] limited by the max size in bytes.
+func NewMemoryCache(maxSize int64) *MemoryCache {
+	return &MemoryCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns a cached entry by key.
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+
+	return el.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores an entry by key and evicts least recently used entries if the max size is exceeded.
+func (c *MemoryCache) Set(key string, e *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+
+	c.entries[key] = c.lru.PushFront(&memoryCacheItem{
+		key:   key,
+		entry: e,
+	})
+	c.size += e.Size()
+
+	for c.size > c.maxSize && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) remove(el *list.Element) {
+	item := c.lru.Remove(el).(*memoryCacheItem)
+	delete(c.entries, item.key)
+	c.size -= item.entry.Size()
+}
@@ -0,0 +1,241 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func Test_newCacheEntry(t *testing.T) {
+	now := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	ttl := time.Hour
+	tests := []struct {
+		name          string
+		header        http.Header
+		wantOK        bool
+		wantExpiresAt time.Time
+	}{
+		{
+			name:          "ok  default ttl",
+			header:        http.Header{},
+			wantOK:        true,
+			wantExpiresAt: now.Add(ttl),
+		},
+		{
+			name: "ok  max-age",
+			header: http.Header{
+				"Cache-Control": {"public, max-age=60"},
+				"Expires":       {now.Add(24 * time.Hour).Format(http.TimeFormat)},
+			},
+			wantOK:        true,
+			wantExpiresAt: now.Add(time.Minute),
+		},
+		{
+			name: "ok  expires",
+			header: http.Header{
+				"Expires": {now.Add(24 * time.Hour).Format(http.TimeFormat)},
+			},
+			wantOK:        true,
+			wantExpiresAt: now.Add(24 * time.Hour),
+		},
+		{
+			name: "ok  invalid expires",
+			header: http.Header{
+				"Expires": {"0"},
+			},
+			wantOK:        true,
+			wantExpiresAt: now,
+		},
+		{
+			name: "ok  no-cache",
+			header: http.Header{
+				"Cache-Control": {"no-cache"},
+			},
+			wantOK:        true,
+			wantExpiresAt: now,
+		},
+		{
+			name: "ok  no-store",
+			header: http.Header{
+				"Cache-Control": {"private, no-store"},
+			},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := newCacheEntry("https://test.com/", tt.header, []byte("body"), now, ttl)
+			if ok != tt.wantOK {
+				t.Fatalf("newCacheEntry() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.ExpiresAt.Equal(tt.wantExpiresAt) {
+				t.Errorf("newCacheEntry() expires at = %v, want %v", got.ExpiresAt, tt.wantExpiresAt)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	fc, err := NewFileCache(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("NewFileCache() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		cache Cache
+	}{
+		{
+			name:  "memory",
+			cache: NewMemoryCache(1024),
+		},
+		{
+			name:  "file",
+			cache: fc,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.cache.Get("a"); ok {
+				t.Fatalf("Cache.Get() ok = %v, want %v", ok, false)
+			}
+
+			body := make([]byte, 400)
+			for _, key := range []string{"a", "b", "c"} {
+				if err := tt.cache.Set(key, &CacheEntry{URL: key, Body: body}); err != nil {
+					t.Fatalf("Cache.Set() error = %v", err)
+				}
+				time.Sleep(10 * time.Millisecond) // hint: distinct modification times of files
+			}
+
+			if _, ok := tt.cache.Get("a"); ok {
+				t.Errorf("Cache.Get() least recently used entry ok = %v, want %v", ok, false)
+			}
+
+			got, ok := tt.cache.Get("c")
+			if !ok {
+				t.Fatalf("Cache.Get() ok = %v, want %v", ok, true)
+			}
+			if got.URL != "c" || len(got.Body) != len(body) {
+				t.Errorf("Cache.Get() = %+v, want entry c", got)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_Cache(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/no-store" {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		_, _ = w.Write([]byte{0xcf, 0xf0, 0xe8, 0xe2, 0xe5, 0xf2}) // hint: "Привет" in windows-1251
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	f, err := New(u, charmap.Windows1251, WithCache(NewMemoryCache(1024), time.Hour), WithRobots(false))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		path         string
+		wantRequests int32
+	}{
+		{path: "/cached", wantRequests: 1},
+		{path: "/cached", wantRequests: 1},
+		{path: "/no-store", wantRequests: 2},
+		{path: "/no-store", wantRequests: 3},
+	}
+	for _, tt := range tests {
+		got, err := f.Fetch(context.Background(), tt.path)
+		if err != nil {
+			t.Fatalf("Fetcher.Fetch() error = %v", err)
+		}
+		if got != "Привет" {
+			t.Errorf("Fetcher.Fetch() = %q, want %q", got, "Привет")
+		}
+		if n := atomic.LoadInt32(&requests); n != tt.wantRequests {
+			t.Errorf("Fetcher.Fetch(%s) requests = %d, want %d", tt.path, n, tt.wantRequests)
+		}
+	}
+}
+
+func TestFetcher_Fetch_Conditional(t *testing.T) {
+	lastModified := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	tests := []struct {
+		name   string
+		header http.Header
+		match  func(r *http.Request) bool
+	}{
+		{
+			name: "etag",
+			header: http.Header{
+				"Etag": {`"v1"`},
+			},
+			match: func(r *http.Request) bool {
+				return r.Header.Get("If-None-Match") == `"v1"`
+			},
+		},
+		{
+			name: "last modified",
+			header: http.Header{
+				"Last-Modified": {lastModified},
+			},
+			match: func(r *http.Request) bool {
+				return r.Header.Get("If-Modified-Since") == lastModified
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests, notModified int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				for k, vs := range tt.header {
+					w.Header()[k] = vs
+				}
+				w.Header().Set("Cache-Control", "no-cache")
+				if tt.match(r) {
+					atomic.AddInt32(&notModified, 1)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = w.Write([]byte("body"))
+			}))
+			defer srv.Close()
+
+			u, _ := url.Parse(srv.URL)
+			f, err := New(u, charmap.Windows1251, WithCache(NewMemoryCache(1024), time.Hour), WithRobots(false))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			for i := 0; i < 3; i++ {
+				got, err := f.Fetch(context.Background(), "/")
+				if err != nil {
+					t.Fatalf("Fetcher.Fetch() error = %v", err)
+				}
+				if got != "body" {
+					t.Errorf("Fetcher.Fetch() = %q, want %q", got, "body")
+				}
+			}
+
+			if n := atomic.LoadInt32(&requests); n != 3 {
+				t.Errorf("Fetcher.Fetch() requests = %d, want %d", n, 3)
+			}
+			if n := atomic.LoadInt32(&notModified); n != 2 {
+				t.Errorf("Fetcher.Fetch() not modified responses = %d, want %d", n, 2)
+			}
+		})
+	}
+}
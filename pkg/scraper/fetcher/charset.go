@@ -0,0 +1,49 @@
+package fetcher
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// metaCharsetRegexp matches both <meta charset="..."> and <meta http-equiv="Content-Type" content="...; charset=...">.
+var metaCharsetRegexp = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([\w.:-]+)`)
+
+// metaPrescanSize is the size of the body prefix scanned for the meta charset declaration.
+const metaPrescanSize = 1024
+
+// detectEncoding returns the encoding of the body declared by the BOM,
+// the charset parameter of the Content-Type header or the meta tag, in order of precedence.
+// The fallback encoding is returned if the encoding is unknown.
+func detectEncoding(contentType string, body []byte, fallback encoding.Encoding) encoding.Encoding {
+	switch {
+	case bytes.HasPrefix(body, []byte{0xef, 0xbb, 0xbf}):
+		return unicode.UTF8BOM
+	case bytes.HasPrefix(body, []byte{0xfe, 0xff}):
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	case bytes.HasPrefix(body, []byte{0xff, 0xfe}):
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	}
+
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		if e, _ := charset.Lookup(params["charset"]); e != nil {
+			return e
+		}
+	}
+
+	prefix := body
+	if len(prefix) > metaPrescanSize {
+		prefix = prefix[:metaPrescanSize]
+	}
+	if m := metaCharsetRegexp.FindSubmatch(prefix); m != nil {
+		if e, _ := charset.Lookup(string(m[1])); e != nil {
+			return e
+		}
+	}
+
+	return fallback
+}
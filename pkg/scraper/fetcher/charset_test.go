@@ -0,0 +1,91 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestFetcher_Fetch_Charset(t *testing.T) {
+	cp1251 := []byte{0xcf, 0xf0, 0xe8, 0xe2, 0xe5, 0xf2}  // hint: "Привет" in windows-1251
+	koi8r := []byte{0xf0, 0xd2, 0xc9, 0xd7, 0xc5, 0xd4}   // hint: "Привет" in koi8-r
+	utf16le := []byte{0x1f, 0x04, 0x40, 0x04, 0x38, 0x04} // hint: "При" in utf-16le
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        string
+	}{
+		{
+			name: "fallback",
+			body: cp1251,
+			want: "Привет",
+		},
+		{
+			name:        "content type",
+			contentType: "text/html; charset=UTF-8",
+			body:        []byte("Привет"),
+			want:        "Привет",
+		},
+		{
+			name:        "unknown content type charset",
+			contentType: "text/html; charset=unknown",
+			body:        cp1251,
+			want:        "Привет",
+		},
+		{
+			name: "meta charset",
+			body: append([]byte(`<html><head><meta charset="koi8-r"></head>`), koi8r...),
+			want: `<html><head><meta charset="koi8-r"></head>Привет`,
+		},
+		{
+			name: "meta http-equiv",
+			body: append([]byte(`<meta http-equiv="Content-Type" content="text/html; charset=utf-8">`), "Привет"...),
+			want: `<meta http-equiv="Content-Type" content="text/html; charset=utf-8">Привет`,
+		},
+		{
+			name:        "content type wins meta",
+			contentType: "text/html; charset=koi8-r",
+			body:        append([]byte(`<meta charset="utf-8">`), koi8r...),
+			want:        `<meta charset="utf-8">Привет`,
+		},
+		{
+			name:        "utf-8 bom",
+			contentType: "text/html; charset=windows-1251",
+			body:        append([]byte{0xef, 0xbb, 0xbf}, "Привет"...),
+			want:        "Привет",
+		},
+		{
+			name: "utf-16le bom",
+			body: append([]byte{0xff, 0xfe}, utf16le...),
+			want: "При",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write(tt.body)
+			}))
+			defer srv.Close()
+
+			u, _ := url.Parse(srv.URL)
+			f, err := New(u, charmap.Windows1251, WithRobots(false))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := f.Fetch(context.Background(), "/")
+			if err != nil {
+				t.Fatalf("Fetcher.Fetch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Fetcher.Fetch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
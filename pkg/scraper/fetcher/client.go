@@ -0,0 +1,78 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ClientConfig is the http client configuration for [NewClient].
+type ClientConfig struct {
+	// Timeout is the time limit of a single request including reading the body, zero means no timeout.
+	Timeout time.Duration
+	// ProxyURL is the HTTP(S) proxy, the proxy is taken from the environment if it's nil.
+	ProxyURL *url.URL
+	// CAFile is the path to the PEM bundle of CA certificates trusted in addition to the system ones.
+	CAFile string
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+// NewClient returns a pointer to the new instance of [http.Client] or an error.
+func NewClient(cfg ClientConfig) (*http.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+
+	if cfg.ProxyURL != nil {
+		t.Proxy = http.ProxyURL(cfg.ProxyURL)
+	}
+
+	if cfg.CAFile != "" {
+		pool, err := loadCertPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		t.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &http.Client{
+		Transport: t,
+		Timeout:   cfg.Timeout,
+	}, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool() // hint: the system pool is unavailable on some platforms
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read a CA bundle: %w", err)
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("failed to parse a CA bundle: no certificates found in %s", path)
+	}
+
+	return pool, nil
+}
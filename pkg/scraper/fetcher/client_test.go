@@ -0,0 +1,124 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestNewClient(t *testing.T) {
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tls"))
+	}))
+	defer tlsSrv.Close()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("proxy " + r.URL.Host))
+	}))
+	defer proxy.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsSrv.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	tests := []struct {
+		name    string
+		cfg     ClientConfig
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ok  ca file",
+			cfg: ClientConfig{
+				CAFile: caFile,
+			},
+			url:  tlsSrv.URL,
+			want: "tls",
+		},
+		{
+			name: "ok  proxy",
+			cfg: ClientConfig{
+				ProxyURL: proxyURL,
+			},
+			url:  "http://test.com/",
+			want: "proxy test.com",
+		},
+		{
+			name:    "err  unknown authority",
+			cfg:     ClientConfig{},
+			url:     tlsSrv.URL,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			u, _ := url.Parse(tt.url)
+			f, err := New(u, charmap.Windows1251, WithClient(client), WithRobots(false))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := f.Fetch(context.Background(), "/")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetcher.Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Fetcher.Fetch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewClient(ClientConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Errorf("NewClient() error = %v, want error for a missing CA file", err)
+	}
+}
+
+func TestFetcher_Fetch_Header(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	f, err := New(
+		u,
+		charmap.Windows1251,
+		WithRobots(false),
+		WithUserAgent("TestBot/1.0"),
+		WithHeader(http.Header{
+			"accept-language": {"ru"},
+			"User-Agent":      {"ignored"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := f.Fetch(context.Background(), "/"); err != nil {
+		t.Fatalf("Fetcher.Fetch() error = %v", err)
+	}
+
+	if v := got.Get("Accept-Language"); v != "ru" {
+		t.Errorf("Accept-Language = %q, want %q", v, "ru")
+	}
+	if v := got.Get("User-Agent"); v != "TestBot/1.0" {
+		t.Errorf("User-Agent = %q, want %q", v, "TestBot/1.0")
+	}
+}
@@ -0,0 +1,95 @@
+package fetcher
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StatusError is an error returned when the server responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the delay requested by the server in the Retry-After header, zero if missing.
+	RetryAfter time.Duration
+}
+
+func newStatusError(res *http.Response, now time.Time) *StatusError {
+	return &StatusError{
+		StatusCode: res.StatusCode,
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), now),
+	}
+}
+
+// Error returns an error message.
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("server did not respond successfully - status code %d", err.StatusCode)
+}
+
+// NotFound reports whether the server responded that the resource is not found.
+func (err *StatusError) NotFound() bool {
+	return err.StatusCode == http.StatusNotFound || err.StatusCode == http.StatusGone
+}
+
+// DisallowedError is an error returned when the URL is disallowed by the robots.txt of the origin.
+type DisallowedError struct {
+	URL string
+}
+
+// Error returns an error message.
+func (err *DisallowedError) Error() string {
+	return fmt.Sprintf("url %s is disallowed by robots.txt", err.URL)
+}
+
+// BodyTooLargeError is an error returned when the response body exceeds the max size.
+type BodyTooLargeError struct {
+	URL     string
+	MaxSize int64
+}
+
+// Error returns an error message.
+func (err *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body of %s exceeds the max size of %d bytes", err.URL, err.MaxSize)
+}
+
+// ContentTypeError is an error returned when the content type of the response is not allowed.
+type ContentTypeError struct {
+	URL         string
+	ContentType string
+}
+
+// Error returns an error message.
+func (err *ContentTypeError) Error() string {
+	return fmt.Sprintf("content type %q of %s is not allowed", err.ContentType, err.URL)
+}
+
+// FixtureNotFoundError is an error returned when the fixture of the path is not recorded.
+type FixtureNotFoundError struct {
+	Path string
+}
+
+// Error returns an error message.
+func (err *FixtureNotFoundError) Error() string {
+	return fmt.Sprintf("fixture of %s is not found", err.Path)
+}
+
+// parseRetryAfter parses the Retry-After header value in seconds or in the HTTP-date format.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
@@ -2,47 +2,94 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"math/rand"
+	"mime"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
-	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding"
+	"golang.org/x/time/rate"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/internal/flight"
 )
 
 // Fetcher is an implementation of an eager content fetcher.
 type Fetcher struct {
-	baseURL  *url.URL
-	encoding *charmap.Charmap
-	client   httpClient
-	retry    *RetryConfig
+	baseURL   *url.URL
+	encoding  encoding.Encoding
+	client    httpClient
+	retry     *RetryConfig
+	retryable RetryPolicy
+	clock     Clock
+	rnd       Rand
+	cache     Cache
+	cacheTTL  time.Duration
+	rateLimit *RateLimitConfig
+	limiter   *rate.Limiter
+	userAgent string
+	header    http.Header
+	robots    bool
+	breaker   *CircuitBreaker
+
+	maxBodySize  int64
+	contentTypes []string
+
+	robotsCache *robotsCache
+	flight      *flight.Group[*Response]
 }
 
+// DefaultUserAgent is the default User-Agent header sent by the [Fetcher].
+const DefaultUserAgent = "LindenHoneyScraper/1.0 (+https://github.com/linden-honey/linden-honey-scraper-go)"
+
 // RetryConfig is the retry configuration for [Fetcher].
+//
+// The meaning of the intervals depends on the backoff strategy, see [BackoffStrategy],
+// the exponential strategy is used if the strategy is empty.
 type RetryConfig struct {
 	Attempts          int
+	Strategy          BackoffStrategy
 	MinInterval       time.Duration
 	MaxInterval       time.Duration
 	Factor            time.Duration
 	MaxJitterInterval time.Duration
 }
 
+// RateLimitConfig is the rate limit configuration for [Fetcher].
+type RateLimitConfig struct {
+	RPS   float64
+	Burst int
+}
+
 type httpClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
 // New returns a pointer to the new instance of [Fetcher] or an error.
+//
+// The encoding of a response is detected from the BOM, the Content-Type header and the meta tag,
+// the passed encoding is used as a fallback.
 func New(
 	baseURL *url.URL,
-	encoding *charmap.Charmap,
+	enc encoding.Encoding,
 	opts ...Option,
 ) (*Fetcher, error) {
 	f := &Fetcher{
-		baseURL:  baseURL,
-		encoding: encoding,
-		client:   new(http.Client),
+		baseURL:   baseURL,
+		encoding:  enc,
+		client:    new(http.Client),
+		retryable: DefaultRetryPolicy,
+		clock:     realClock{},
+		rnd:       newLockedRand(time.Now().UnixNano()),
+		userAgent: DefaultUserAgent,
+		robots:    true,
+		robotsCache: &robotsCache{
+			entries: make(map[string]*robotsEntry),
+		},
+		flight: new(flight.Group[*Response]),
 	}
 
 	for _, opt := range opts {
@@ -53,6 +100,10 @@ func New(
 		return nil, err
 	}
 
+	if f.rateLimit != nil {
+		f.limiter = rate.NewLimiter(rate.Limit(f.rateLimit.RPS), f.rateLimit.Burst)
+	}
+
 	return f, nil
 }
 
@@ -73,45 +124,215 @@ func WithRetry(cfg *RetryConfig) Option {
 	}
 }
 
+// WithRetryPolicy sets the policy deciding which errors are retried by the [Fetcher].
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(f *Fetcher) {
+		f.retryable = p
+	}
+}
+
+// WithClock sets the source of time for the [Fetcher].
+func WithClock(c Clock) Option {
+	return func(f *Fetcher) {
+		f.clock = c
+	}
+}
+
+// WithRand sets the source of random numbers used for retry jitter by the [Fetcher].
+// The source must be safe for concurrent use.
+func WithRand(r Rand) Option {
+	return func(f *Fetcher) {
+		f.rnd = r
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent by the [Fetcher].
+//
+// The product token of the user agent is used to find the matching robots.txt rules.
+func WithUserAgent(userAgent string) Option {
+	return func(f *Fetcher) {
+		f.userAgent = userAgent
+	}
+}
+
+// WithHeader sets the additional headers sent by the [Fetcher] with each request.
+//
+// The User-Agent and conditional headers set by the [Fetcher] take precedence.
+func WithHeader(header http.Header) Option {
+	return func(f *Fetcher) {
+		f.header = header
+	}
+}
+
+// WithRobots enables or disables robots.txt compliance for the [Fetcher].
+//
+// If enabled, the [Fetcher] refuses to fetch paths disallowed by the robots.txt of the origin
+// with a [*DisallowedError] and honours the Crawl-delay. It's enabled by default.
+func WithRobots(robots bool) Option {
+	return func(f *Fetcher) {
+		f.robots = robots
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker for the [Fetcher].
+//
+// While the circuit is open, requests fail fast with [ErrCircuitOpen] and are not retried,
+// cached responses are still served.
+// The breaker may be shared by fetchers of the same source.
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(f *Fetcher) {
+		f.breaker = cb
+	}
+}
+
+// WithRateLimit sets the rate limit configuration for the [Fetcher].
+//
+// The limit is applied to all requests sent by the [Fetcher] instance including retries,
+// cached responses are not limited.
+func WithRateLimit(cfg *RateLimitConfig) Option {
+	return func(f *Fetcher) {
+		f.rateLimit = cfg
+	}
+}
+
+// WithMaxBodySize sets the max size of a response body in bytes for the [Fetcher].
+//
+// The fetch fails with a [*BodyTooLargeError] if the body is larger. It's unlimited by default.
+func WithMaxBodySize(size int64) Option {
+	return func(f *Fetcher) {
+		f.maxBodySize = size
+	}
+}
+
+// WithAllowedContentTypes sets the media types of a response allowed by the [Fetcher], e.g. "text/html".
+//
+// The fetch fails with a [*ContentTypeError] if the media type is not allowed. All types are allowed by default.
+func WithAllowedContentTypes(types ...string) Option {
+	return func(f *Fetcher) {
+		f.contentTypes = types
+	}
+}
+
+// WithCache sets the cache of responses for the [Fetcher].
+//
+// Responses are cached according to the Cache-Control and Expires headers,
+// the ttl is used if the headers are missing.
+func WithCache(c Cache, ttl time.Duration) Option {
+	return func(f *Fetcher) {
+		f.cache = c
+		f.cacheTTL = ttl
+	}
+}
+
+type response struct {
+	header http.Header
+	body   []byte
+}
+
+// Response is a fetched response with the decoded body.
+type Response struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
 // Fetch sends a GET-request under a relative path and returns the content as a string
 // or returns an error.
 func (f *Fetcher) Fetch(ctx context.Context, path string) (string, error) {
+	res, err := f.FetchResponse(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Body, nil
+}
+
+// FetchResponse sends a GET-request under a relative path and returns the response
+// or returns an error.
+//
+// Concurrent fetches of the same URL are collapsed into a single request,
+// the callers share the response and must not modify it.
+func (f *Fetcher) FetchResponse(ctx context.Context, path string) (*Response, error) {
 	u, err := f.baseURL.Parse(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse an URL: %w", err)
+		return nil, fmt.Errorf("failed to parse an URL: %w", err)
+	}
+
+	return f.flight.Do(ctx, u.String(), func(ctx context.Context) (*Response, error) {
+		return f.fetchResponse(ctx, u)
+	})
+}
+
+func (f *Fetcher) fetchResponse(ctx context.Context, u *url.URL) (*Response, error) {
+	key := u.String()
+	var cached *CacheEntry
+	if f.cache != nil {
+		if e, ok := f.cache.Get(key); ok {
+			if e.Fresh(f.clock.Now()) {
+				return f.newResponse(key, e.Header, e.Body)
+			}
+
+			cached = e // hint: a stale entry is revalidated with a conditional request
+		}
+	}
+
+	if f.robots {
+		if err := f.checkRobots(ctx, u); err != nil {
+			return nil, err
+		}
 	}
 
+	var (
+		res *response
+		err error
+	)
 	if f.retry != nil {
-		return f.fetchWithRetry(ctx, u)
+		res, err = f.fetchWithRetry(ctx, u, cached)
+	} else {
+		res, err = f.fetch(ctx, u, cached)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return f.fetch(ctx, u)
+	if f.cache != nil {
+		if e, ok := newCacheEntry(key, res.header, res.body, f.clock.Now(), f.cacheTTL); ok {
+			_ = f.cache.Set(key, e) // hint: a failed cache write should not fail the fetch
+		}
+	}
+
+	return f.newResponse(key, res.header, res.body)
 }
 
-func (f *Fetcher) fetchWithRetry(ctx context.Context, u *url.URL) (string, error) {
+// fetchWithRetry retries the fetch on errors accepted by the retry policy.
+// The delay requested by the server in the Retry-After header is honoured,
+// the fetch is not retried if the delay exceeds the max interval.
+func (f *Fetcher) fetchWithRetry(ctx context.Context, u *url.URL, cached *CacheEntry) (*response, error) {
+	b := f.retry.NewBackoff(f.rnd)
 	for attempt := 0; ; attempt++ {
-		res, err := f.fetch(ctx, u)
+		res, err := f.fetch(ctx, u, cached)
 		if err != nil {
-			if attempt == f.retry.Attempts-1 {
-				return "", fmt.Errorf("failed to fetch after attempts=%d: %w", attempt+1, err)
+			if attempt == f.retry.Attempts-1 || !f.retryable(err) {
+				return nil, fmt.Errorf("failed to fetch after attempts=%d: %w", attempt+1, err)
 			}
 
-			rand.Seed(time.Now().UTC().UnixNano())
-			delay := f.retry.Factor * time.Duration(attempt+1)
-			jitter := time.Duration(rand.Float64() * float64(f.retry.Factor))
-			delay = delay + jitter
-			if delay < f.retry.MinInterval {
-				delay = f.retry.MinInterval
-			}
-			if delay > f.retry.MaxInterval {
-				delay = f.retry.MaxInterval
+			delay := b.Next(attempt)
+
+			var serr *StatusError
+			if errors.As(err, &serr) && serr.RetryAfter > delay {
+				if serr.RetryAfter > f.retry.MaxInterval {
+					return nil, fmt.Errorf("failed to fetch after attempts=%d, retry after %s: %w", attempt+1, serr.RetryAfter, err)
+				}
+
+				delay = serr.RetryAfter
 			}
 
 			select {
-			case <-time.After(delay):
+			case <-f.clock.After(delay):
 				continue
 			case <-ctx.Done():
-				return "", fmt.Errorf("failed to retry fetch, attempt=%ds: %w", attempt+1, ctx.Err())
+				return nil, fmt.Errorf("failed to retry fetch, attempt=%ds: %w", attempt+1, ctx.Err())
 			}
 		}
 
@@ -119,29 +340,200 @@ func (f *Fetcher) fetchWithRetry(ctx context.Context, u *url.URL) (string, error
 	}
 }
 
-func (f *Fetcher) fetch(ctx context.Context, u *url.URL) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create a request: %w", err)
+// fetch sends a GET-request guarded by the circuit breaker and returns the response or an error.
+func (f *Fetcher) fetch(ctx context.Context, u *url.URL, cached *CacheEntry) (res *response, err error) {
+	err = f.withBreaker(func() error {
+		res, err = f.send(ctx, u, cached)
+		return err
+	})
+
+	return res, err
+}
+
+// withBreaker calls the function if the circuit breaker allows the request and records the result.
+func (f *Fetcher) withBreaker(fn func() error) error {
+	if f.breaker == nil {
+		return fn()
 	}
 
-	res, err := f.client.Do(req)
+	if err := f.breaker.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	f.breaker.done(err, f.retryable)
+
+	return err
+}
+
+// waitRateLimit waits until the rate limiter allows a request, the delay is measured by the clock of the [Fetcher].
+func (f *Fetcher) waitRateLimit(ctx context.Context) error {
+	now := f.clock.Now()
+	r := f.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return errors.New("request exceeds the rate limiter burst")
+	}
+
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+
+	select {
+	case <-f.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		r.CancelAt(f.clock.Now())
+		return ctx.Err()
+	}
+}
+
+// send sends a GET-request and returns the response or an error.
+// If the cached entry is passed, the request is conditional
+// and the Not Modified response is resolved to the cached body.
+func (f *Fetcher) send(ctx context.Context, u *url.URL, cached *CacheEntry) (*response, error) {
+	if f.limiter != nil {
+		if err := f.waitRateLimit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to wait for a rate limiter: %w", err)
+		}
+	}
+
+	if f.robots {
+		if err := f.waitCrawlDelay(ctx, u); err != nil {
+			return nil, fmt.Errorf("failed to wait for a crawl delay: %w", err)
+		}
+	}
+
+	req, err := f.newRequest(ctx, u.String())
 	if err != nil {
-		return "", fmt.Errorf("failed to proceed request: %w", err)
+		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("server did not respond successfully - status code %d", res.StatusCode)
+	if cached != nil {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		if lm := cached.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to proceed request: %w", err)
 	}
 	defer func() {
 		_ = res.Body.Close()
 	}()
 
-	decoder := f.encoding.NewDecoder()
-	body, err := io.ReadAll(decoder.Reader(res.Body))
+	if res.StatusCode == http.StatusNotModified && cached != nil {
+		header := cached.Header.Clone()
+		for k, vs := range res.Header {
+			header[k] = vs // hint: a Not Modified response updates the stored headers
+		}
+
+		return &response{
+			header: header,
+			body:   cached.Body,
+		}, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, newStatusError(res, f.clock.Now())
+	}
+
+	if err := f.checkContentType(u, res.Header.Get("Content-Type")); err != nil {
+		return nil, err
+	}
+
+	body, err := f.readBody(u, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{
+		header: res.Header,
+		body:   body,
+	}, nil
+}
+
+// newRequest returns a new GET-request with the headers of the [Fetcher].
+func (f *Fetcher) newRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a request: %w", err)
+	}
+
+	for k, vs := range f.header {
+		req.Header[http.CanonicalHeaderKey(k)] = vs
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+
+	return req, nil
+}
+
+// checkContentType returns a [*ContentTypeError] if the media type is not allowed.
+func (f *Fetcher) checkContentType(u *url.URL, contentType string) error {
+	if len(f.contentTypes) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, t := range f.contentTypes {
+		if strings.EqualFold(mediaType, t) {
+			return nil
+		}
+	}
+
+	return &ContentTypeError{
+		URL:         u.String(),
+		ContentType: contentType,
+	}
+}
+
+// readBody reads the response body and returns a [*BodyTooLargeError] if the max body size is exceeded.
+func (f *Fetcher) readBody(u *url.URL, res *http.Response) ([]byte, error) {
+	if f.maxBodySize <= 0 {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read a response: %w", err)
+		}
+
+		return body, nil
+	}
+
+	tooLarge := &BodyTooLargeError{
+		URL:     u.String(),
+		MaxSize: f.maxBodySize,
+	}
+	if res.ContentLength > f.maxBodySize {
+		return nil, tooLarge // hint: fail before reading if the size is known
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, f.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read a response: %w", err)
+	}
+
+	if int64(len(body)) > f.maxBodySize {
+		return nil, tooLarge
+	}
+
+	return body, nil
+}
+
+func (f *Fetcher) newResponse(key string, header http.Header, body []byte) (*Response, error) {
+	enc := detectEncoding(header.Get("Content-Type"), body, f.encoding)
+	data, err := enc.NewDecoder().Bytes(body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read a response: %w", err)
+		return nil, fmt.Errorf("failed to decode a response: %w", err)
 	}
 
-	return string(body), nil
+	return &Response{
+		URL:        key,
+		StatusCode: http.StatusOK, // hint: a Not Modified response is resolved to the cached one
+		Header:     header,
+		Body:       string(data),
+	}, nil
 }
@@ -0,0 +1,341 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestFetcher_Fetch_RateLimit(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	clock := &fakeClock{now: time.Now()}
+	f, err := New(
+		u,
+		charmap.Windows1251,
+		WithRateLimit(&RateLimitConfig{
+			RPS:   20,
+			Burst: 2,
+		}),
+		WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i := 0; i < 6; i++ {
+		if _, err := f.Fetch(context.Background(), fmt.Sprintf("/%d", i)); err != nil {
+			t.Fatalf("Fetcher.Fetch() error = %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 7 { // 6 pages and the robots.txt
+		t.Errorf("Fetcher.Fetch() requests = %d, want %d", got, 7)
+	}
+
+	// 2 requests are allowed by the burst, each of the other 4 requests waits for a token
+	if len(clock.delays) != 4 {
+		t.Fatalf("Fetcher.Fetch() delays = %v, want %d delays", clock.delays, 4)
+	}
+	for _, d := range clock.delays {
+		if d < 49*time.Millisecond || d > 51*time.Millisecond {
+			t.Errorf("Fetcher.Fetch() delay = %v, want %v", d, 50*time.Millisecond)
+		}
+	}
+}
+
+func TestFetcher_Fetch_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		retryAfter   string
+		opts         []Option
+		wantAttempts int32
+		wantStatus   int
+		wantDelays   []time.Duration
+	}{
+		{
+			name:         "ok  retry server error",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 3,
+			wantDelays:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:         "ok  retry after",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "1",
+			wantAttempts: 2,
+			wantDelays:   []time.Duration{time.Second},
+		},
+		{
+			name:         "err  not found is not retried",
+			statuses:     []int{http.StatusNotFound},
+			wantAttempts: 1,
+			wantStatus:   http.StatusNotFound,
+		},
+		{
+			name:         "err  retry after exceeds max interval",
+			statuses:     []int{http.StatusTooManyRequests},
+			retryAfter:   "3600",
+			wantAttempts: 1,
+			wantStatus:   http.StatusTooManyRequests,
+		},
+		{
+			name:     "err  custom retry policy",
+			statuses: []int{http.StatusServiceUnavailable},
+			opts: []Option{
+				WithRetryPolicy(func(err error) bool {
+					return false
+				}),
+			},
+			wantAttempts: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:         "err  attempts exceeded",
+			statuses:     []int{http.StatusInternalServerError},
+			wantAttempts: 3,
+			wantStatus:   http.StatusInternalServerError,
+			wantDelays:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&attempts, 1))
+				status := tt.statuses[len(tt.statuses)-1]
+				if n <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			u, _ := url.Parse(srv.URL)
+			clock := &fakeClock{now: time.Now()}
+			opts := append([]Option{
+				WithRobots(false),
+				WithClock(clock),
+				WithRand(fakeRand{fraction: 1}),
+				WithRetry(&RetryConfig{
+					Attempts:    3,
+					Strategy:    BackoffExponential,
+					MinInterval: 10 * time.Millisecond,
+					MaxInterval: 2 * time.Second,
+					Factor:      100 * time.Millisecond,
+				}),
+			}, tt.opts...)
+			f, err := New(u, charmap.Windows1251, opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			_, err = f.Fetch(context.Background(), "/")
+			if (err != nil) != (tt.wantStatus != 0) {
+				t.Fatalf("Fetcher.Fetch() error = %v, wantStatus %v", err, tt.wantStatus)
+			}
+			if tt.wantStatus != 0 {
+				var serr *StatusError
+				if !errors.As(err, &serr) || serr.StatusCode != tt.wantStatus {
+					t.Errorf("Fetcher.Fetch() error = %v, wantStatus %v", err, tt.wantStatus)
+				}
+			}
+			if n := atomic.LoadInt32(&attempts); n != tt.wantAttempts {
+				t.Errorf("Fetcher.Fetch() attempts = %d, want %d", n, tt.wantAttempts)
+			}
+			if len(clock.delays) != 0 || len(tt.wantDelays) != 0 {
+				if !reflect.DeepEqual(clock.delays, tt.wantDelays) {
+					t.Errorf("Fetcher.Fetch() delays = %v, want %v", clock.delays, tt.wantDelays)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "server error",
+			err:  &StatusError{StatusCode: http.StatusInternalServerError},
+			want: true,
+		},
+		{
+			name: "too many requests",
+			err:  &StatusError{StatusCode: http.StatusTooManyRequests},
+			want: true,
+		},
+		{
+			name: "not found",
+			err:  &StatusError{StatusCode: http.StatusNotFound},
+			want: false,
+		},
+		{
+			name: "network error",
+			err:  &url.Error{Op: "Get", URL: "https://test.com/", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}},
+			want: true,
+		},
+		{
+			name: "url error",
+			err:  &url.Error{Op: "Get", URL: "test://test.com/", Err: errors.New("unsupported protocol scheme")},
+			want: false,
+		},
+		{
+			name: "context canceled",
+			err:  &url.Error{Op: "Get", URL: "https://test.com/", Err: context.Canceled},
+			want: false,
+		},
+		{
+			name: "disallowed",
+			err:  &DisallowedError{URL: "https://test.com/"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRetryPolicy(tt.err); got != tt.want {
+				t.Errorf("DefaultRetryPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_Limits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+		case "/chunked":
+			w.Header().Set("Content-Type", "text/html")
+			w.(http.Flusher).Flush() // hint: the content length is unknown
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    []Option
+		want    string
+		wantErr interface{}
+	}{
+		{
+			name: "ok",
+			path: "/",
+			opts: []Option{WithMaxBodySize(10), WithAllowedContentTypes("text/plain", "TEXT/HTML")},
+			want: "0123456789",
+		},
+		{
+			name:    "err  content length exceeds max body size",
+			path:    "/",
+			opts:    []Option{WithMaxBodySize(9)},
+			wantErr: new(*BodyTooLargeError),
+		},
+		{
+			name:    "err  chunked body exceeds max body size",
+			path:    "/chunked",
+			opts:    []Option{WithMaxBodySize(9)},
+			wantErr: new(*BodyTooLargeError),
+		},
+		{
+			name:    "err  content type is not allowed",
+			path:    "/image",
+			opts:    []Option{WithAllowedContentTypes("text/html")},
+			wantErr: new(*ContentTypeError),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(srv.URL)
+			f, err := New(u, charmap.Windows1251, append([]Option{WithRobots(false)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := f.Fetch(context.Background(), tt.path)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Fatalf("Fetcher.Fetch() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetcher.Fetch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Fetcher.Fetch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_Dedup(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	f, err := New(u, charmap.Windows1251, WithRobots(false))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			got, err := f.Fetch(context.Background(), "/")
+			if err != nil {
+				t.Errorf("Fetcher.Fetch() error = %v", err)
+				return
+			}
+			if got != "body" {
+				t.Errorf("Fetcher.Fetch() = %q, want %q", got, "body")
+			}
+		}()
+	}
+	for f.flight.Callers(srv.URL+"/") < 10 {
+		runtime.Gosched() // hint: let all callers join the request
+	}
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Fetcher.Fetch() requests = %d, want %d", n, 1)
+	}
+}
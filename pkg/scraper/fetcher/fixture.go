@@ -0,0 +1,204 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// Fixture is a recorded response of a fetch.
+type Fixture struct {
+	Path       string      `json:"path"`
+	URL        string      `json:"url,omitempty"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// ResponseFetcher is a component for fetching responses.
+type ResponseFetcher interface {
+	FetchResponse(ctx context.Context, path string) (*Response, error)
+}
+
+// Recorder is a fetcher recording responses of the wrapped fetcher to a fixture directory.
+//
+// Responses with unexpected status codes are recorded too, other errors are not.
+// The body is recorded decoded to UTF-8, so the headers are adjusted to describe the recorded body.
+type Recorder struct {
+	mu   sync.Mutex
+	next ResponseFetcher
+	dir  string
+}
+
+// NewRecorder returns a pointer to the new instance of [Recorder] or an error.
+func NewRecorder(next ResponseFetcher, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create a fixture directory: %w", err)
+	}
+
+	return &Recorder{
+		next: next,
+		dir:  dir,
+	}, nil
+}
+
+// Fetch fetches the content with the wrapped fetcher and records the response.
+func (r *Recorder) Fetch(ctx context.Context, path string) (string, error) {
+	res, err := r.next.FetchResponse(ctx, path)
+	if err != nil {
+		var serr *StatusError
+		if !errors.As(err, &serr) {
+			return "", err
+		}
+
+		if werr := r.write(&Fixture{Path: path, StatusCode: serr.StatusCode}); werr != nil {
+			return "", werr
+		}
+
+		return "", err
+	}
+
+	if err := r.write(&Fixture{
+		Path:       path,
+		URL:        res.URL,
+		StatusCode: res.StatusCode,
+		Header:     fixtureHeader(res.Header),
+		Body:       res.Body,
+	}); err != nil {
+		return "", err
+	}
+
+	return res.Body, nil
+}
+
+func (r *Recorder) write(fx *Fixture) error {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false) // hint: keep the recorded html readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(fx); err != nil {
+		return fmt.Errorf("failed to encode a fixture: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tmp, err := os.CreateTemp(r.dir, "*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create a fixture file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write a fixture: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close a fixture file: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to change the mode of a fixture file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), fixturePath(r.dir, fx.Path)); err != nil {
+		return fmt.Errorf("failed to rename a fixture file: %w", err)
+	}
+
+	return nil
+}
+
+// fixtureHeader returns a copy of the response header describing the body decoded to UTF-8.
+func fixtureHeader(h http.Header) http.Header {
+	h = h.Clone()
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+
+	if mt, params, err := mime.ParseMediaType(h.Get("Content-Type")); err == nil {
+		if _, ok := params["charset"]; ok {
+			params["charset"] = "utf-8"
+			h.Set("Content-Type", mime.FormatMediaType(mt, params))
+		}
+	}
+
+	return h
+}
+
+// Replayer is a fetcher serving responses from a fixture directory without network.
+type Replayer struct {
+	dir string
+}
+
+// NewReplayer returns a pointer to the new instance of [Replayer] or an error.
+func NewReplayer(dir string) (*Replayer, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a fixture directory: %w", err)
+	}
+
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("fixture path %s is not a directory", dir)
+	}
+
+	return &Replayer{
+		dir: dir,
+	}, nil
+}
+
+// Fetch returns the recorded content or returns a [*FixtureNotFoundError]
+// or a [*StatusError] for a recorded unexpected status code.
+func (r *Replayer) Fetch(ctx context.Context, path string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(fixturePath(r.dir, path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", &FixtureNotFoundError{
+				Path: path,
+			}
+		}
+
+		return "", fmt.Errorf("failed to read a fixture: %w", err)
+	}
+
+	fx := new(Fixture)
+	if err := json.Unmarshal(data, fx); err != nil {
+		return "", fmt.Errorf("failed to decode a fixture: %w", err)
+	}
+
+	if fx.StatusCode != http.StatusOK {
+		return "", &StatusError{
+			StatusCode: fx.StatusCode,
+		}
+	}
+
+	return fx.Body, nil
+}
+
+var unsafeFileNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fixturePath returns a readable file name of the fixture unique for the path.
+func fixturePath(dir string, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	name := unsafeFileNameRegexp.ReplaceAllString(path, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return filepath.Join(dir, name+"-"+hex.EncodeToString(sum[:4])+".json")
+}
@@ -0,0 +1,196 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestRecorder_Replayer(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+		_, _ = w.Write([]byte{0xcf, 0xf0, 0xe8, 0xe2, 0xe5, 0xf2}) // hint: "Привет" in windows-1251
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	f, err := New(u, charmap.Windows1251, WithRobots(false))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	rec, err := NewRecorder(f, dir)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	rep, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr interface{}
+	}{
+		{
+			name: "ok",
+			path: "text_print.php?area=go_texts&id=1",
+			want: "Привет",
+		},
+		{
+			name:    "err  status",
+			path:    "/missing",
+			wantErr: new(*StatusError),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, fr := range []struct {
+				name  string
+				Fetch func(ctx context.Context, path string) (string, error)
+			}{
+				{name: "Recorder", Fetch: rec.Fetch},
+				{name: "Replayer", Fetch: rep.Fetch},
+			} {
+				got, err := fr.Fetch(context.Background(), tt.path)
+				if tt.wantErr != nil {
+					if !errors.As(err, tt.wantErr) {
+						t.Errorf("%s.Fetch() error = %v, want %T", fr.name, err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s.Fetch() error = %v", fr.name, err)
+				}
+				if got != tt.want {
+					t.Errorf("%s.Fetch() = %q, want %q", fr.name, got, tt.want)
+				}
+			}
+		})
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("requests = %d, want %d", n, 2)
+	}
+
+	if _, err := rep.Fetch(context.Background(), "/unknown"); !errors.As(err, new(*FixtureNotFoundError)) {
+		t.Errorf("Replayer.Fetch() error = %v, want %T", err, new(*FixtureNotFoundError))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := rep.Fetch(ctx, "text_print.php?area=go_texts&id=1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Replayer.Fetch() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRecorder_Fetch_Concurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	f, err := New(u, charmap.Windows1251, WithRobots(false))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	rec, err := NewRecorder(f, dir)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := rec.Fetch(context.Background(), "/"); err != nil {
+				t.Errorf("Recorder.Fetch() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read the fixture directory: %v", err)
+	}
+	if len(des) != 1 {
+		t.Fatalf("fixture files = %d, want %d", len(des), 1)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, des[0].Name()))
+	if err != nil {
+		t.Fatalf("failed to read the fixture: %v", err)
+	}
+	fx := new(Fixture)
+	if err := json.Unmarshal(data, fx); err != nil {
+		t.Fatalf("failed to decode the fixture: %v", err)
+	}
+	if fx.Body != "body" {
+		t.Errorf("fixture body = %q, want %q", fx.Body, "body")
+	}
+}
+
+func Test_fixtureHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   http.Header
+	}{
+		{
+			name: "ok",
+			header: http.Header{
+				"Content-Type":   {"text/html; charset=windows-1251"},
+				"Content-Length": {"6"},
+				"Etag":           {`"v1"`},
+			},
+			want: http.Header{
+				"Content-Type": {"text/html; charset=utf-8"},
+				"Etag":         {`"v1"`},
+			},
+		},
+		{
+			name: "ok  without charset",
+			header: http.Header{
+				"Content-Type":     {"text/html"},
+				"Content-Encoding": {"gzip"},
+			},
+			want: http.Header{
+				"Content-Type": {"text/html"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fixtureHeader(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fixtureHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
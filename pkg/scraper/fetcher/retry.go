@@ -0,0 +1,38 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// RetryPolicy reports whether a failed fetch should be retried.
+type RetryPolicy func(err error) bool
+
+// DefaultRetryPolicy retries network errors and responses with 429 or 5xx status codes.
+func DefaultRetryPolicy(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var serr *StatusError
+	if errors.As(err, &serr) {
+		return serr.StatusCode == http.StatusTooManyRequests || serr.StatusCode >= 500
+	}
+
+	// hint: a response body interrupted by the server
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		var nerr net.Error
+		return errors.As(uerr.Err, &nerr) || errors.Is(uerr.Err, io.EOF) || errors.Is(uerr.Err, io.ErrUnexpectedEOF)
+	}
+
+	return false
+}
@@ -0,0 +1,296 @@
+package fetcher
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/internal/flight"
+)
+
+// robots is a set of robots.txt rules applicable to a user agent.
+type robots struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+// parseRobots parses the robots.txt content and returns the rules applicable to the user agent.
+// The rules of the most specific matching group are used, the "*" group is used as a fallback.
+func parseRobots(content string, userAgent string) *robots {
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i != -1 {
+		token = token[:i] // hint: match only the product token of the user agent
+	}
+
+	var (
+		matched  = &robots{}
+		wildcard = &robots{}
+		found    bool
+
+		agents  []string
+		inRules bool
+	)
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if inRules {
+				agents = nil
+				inRules = false
+			}
+			agents = append(agents, strings.ToLower(value))
+
+			continue
+		}
+
+		inRules = true
+		for _, agent := range agents {
+			var r *robots
+			switch {
+			case agent == "*":
+				r = wildcard
+			case agent == token:
+				r = matched
+				found = true
+			default:
+				continue
+			}
+
+			switch key {
+			case "allow", "disallow":
+				if value == "" {
+					continue // hint: an empty rule matches nothing
+				}
+				r.rules = append(r.rules, robotsRule{
+					allow:   key == "allow",
+					pattern: value,
+					re:      compileRobotsPattern(value),
+				})
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					r.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+
+	if found {
+		return matched
+	}
+
+	return wildcard
+}
+
+// Allowed reports whether the path (with a query) is allowed.
+// The longest matching rule wins, allow rules win ties.
+func (r *robots) Allowed(path string) bool {
+	if path == "/robots.txt" {
+		return true
+	}
+
+	var (
+		allowed = true
+		longest = -1
+	)
+	for _, rule := range r.rules {
+		if !rule.re.MatchString(path) {
+			continue
+		}
+
+		if n := len(rule.pattern); n > longest || (n == longest && rule.allow) {
+			allowed = rule.allow
+			longest = n
+		}
+	}
+
+	return allowed
+}
+
+func compileRobotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	return regexp.MustCompile("^" + expr)
+}
+
+const (
+	// robotsTTL is the period of time robots.txt of the origin is cached for.
+	robotsTTL = 24 * time.Hour
+	// robotsRetryTTL is the period of time a server error on robots.txt of the origin is cached for.
+	robotsRetryTTL = time.Minute
+)
+
+type robotsCache struct {
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+	flight  flight.Group[*robots]
+}
+
+type robotsEntry struct {
+	robots    *robots
+	err       error
+	expiresAt time.Time
+	nextAt    time.Time
+}
+
+// checkRobots returns a [*DisallowedError] if the URL is disallowed by the robots.txt of the origin.
+func (f *Fetcher) checkRobots(ctx context.Context, u *url.URL) error {
+	r, err := f.getRobots(ctx, u)
+	if err != nil {
+		return fmt.Errorf("failed to get robots.txt: %w", err)
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	if !r.Allowed(path) {
+		return &DisallowedError{
+			URL: u.String(),
+		}
+	}
+
+	return nil
+}
+
+// getRobots returns the cached robots.txt rules of the origin or fetches them.
+// The cache is not locked during the fetch, concurrent fetches of the same origin are collapsed.
+func (f *Fetcher) getRobots(ctx context.Context, u *url.URL) (*robots, error) {
+	origin := u.Scheme + "://" + u.Host
+
+	f.robotsCache.mu.Lock()
+	e, ok := f.robotsCache.entries[origin]
+	if ok && f.clock.Now().Before(e.expiresAt) {
+		r, err := e.robots, e.err
+		f.robotsCache.mu.Unlock()
+
+		return r, err
+	}
+	f.robotsCache.mu.Unlock()
+
+	return f.robotsCache.flight.Do(ctx, origin, func(ctx context.Context) (*robots, error) {
+		return f.loadRobots(ctx, origin)
+	})
+}
+
+// loadRobots fetches the robots.txt rules of the origin and stores them in the cache.
+// A server error is cached for a short period of time and returned as a temporary [*StatusError].
+func (f *Fetcher) loadRobots(ctx context.Context, origin string) (*robots, error) {
+	var r *robots
+	err := f.withBreaker(func() (err error) {
+		r, err = f.fetchRobots(ctx, origin)
+		return err
+	})
+
+	ttl := robotsTTL
+	var serr *StatusError
+	switch {
+	case errors.As(err, &serr) && serr.StatusCode >= http.StatusInternalServerError:
+		ttl = robotsRetryTTL
+	case err != nil:
+		return nil, err
+	}
+
+	f.robotsCache.mu.Lock()
+	defer f.robotsCache.mu.Unlock()
+
+	e, ok := f.robotsCache.entries[origin]
+	if !ok {
+		e = new(robotsEntry)
+		f.robotsCache.entries[origin] = e
+	}
+	e.robots = r
+	e.err = err
+	e.expiresAt = f.clock.Now().Add(ttl)
+
+	return r, err
+}
+
+func (f *Fetcher) fetchRobots(ctx context.Context, origin string) (*robots, error) {
+	req, err := f.newRequest(ctx, origin+"/robots.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to proceed request: %w", err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		body, err := io.ReadAll(io.LimitReader(res.Body, 512<<10)) // hint: content after 500 KiB may be ignored
+		if err != nil {
+			return nil, fmt.Errorf("failed to read a response: %w", err)
+		}
+
+		return parseRobots(string(body), f.userAgent), nil
+	case res.StatusCode >= 400 && res.StatusCode < 500:
+		return &robots{}, nil // hint: everything is allowed if robots.txt is unavailable
+	default:
+		return nil, newStatusError(res, f.clock.Now())
+	}
+}
+
+// waitCrawlDelay waits until the Crawl-delay of the origin passed since the previous request.
+func (f *Fetcher) waitCrawlDelay(ctx context.Context, u *url.URL) error {
+	f.robotsCache.mu.Lock()
+	e, ok := f.robotsCache.entries[u.Scheme+"://"+u.Host]
+	if !ok || e.robots == nil || e.robots.crawlDelay == 0 {
+		f.robotsCache.mu.Unlock()
+		return nil
+	}
+
+	now := f.clock.Now()
+	at := e.nextAt
+	if at.Before(now) {
+		at = now
+	}
+	e.nextAt = at.Add(e.robots.crawlDelay)
+	f.robotsCache.mu.Unlock()
+
+	select {
+	case <-f.clock.After(at.Sub(now)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,259 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func Test_robots_Allowed(t *testing.T) {
+	content := `
+# comment
+User-agent: *
+Disallow: /admin
+Crawl-delay: 5
+
+User-agent: OtherBot
+User-agent: LindenHoneyScraper
+Disallow: /private
+Disallow: /*.php$
+Allow: /private/public
+Allow: /text_print.php?area=go_texts
+Crawl-delay: 0.5
+`
+	tests := []struct {
+		name           string
+		userAgent      string
+		path           string
+		want           bool
+		wantCrawlDelay time.Duration
+	}{
+		{
+			name:           "ok  specific group allows the path",
+			userAgent:      DefaultUserAgent,
+			path:           "/admin",
+			want:           true,
+			wantCrawlDelay: 500 * time.Millisecond,
+		},
+		{
+			name:           "ok  specific group disallows the path",
+			userAgent:      DefaultUserAgent,
+			path:           "/private/page",
+			want:           false,
+			wantCrawlDelay: 500 * time.Millisecond,
+		},
+		{
+			name:           "ok  longest rule wins",
+			userAgent:      DefaultUserAgent,
+			path:           "/private/public/page",
+			want:           true,
+			wantCrawlDelay: 500 * time.Millisecond,
+		},
+		{
+			name:           "ok  wildcard with anchor",
+			userAgent:      DefaultUserAgent,
+			path:           "/index.php",
+			want:           false,
+			wantCrawlDelay: 500 * time.Millisecond,
+		},
+		{
+			name:           "ok  allow rule with query",
+			userAgent:      DefaultUserAgent,
+			path:           "/text_print.php?area=go_texts&id=1",
+			want:           true,
+			wantCrawlDelay: 500 * time.Millisecond,
+		},
+		{
+			name:           "ok  fallback group",
+			userAgent:      "UnknownBot/2.0",
+			path:           "/admin/page",
+			want:           false,
+			wantCrawlDelay: 5 * time.Second,
+		},
+		{
+			name:           "ok  robots.txt is always allowed",
+			userAgent:      "UnknownBot/2.0",
+			path:           "/robots.txt",
+			want:           true,
+			wantCrawlDelay: 5 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := parseRobots(content, tt.userAgent)
+			if got := r.Allowed(tt.path); got != tt.want {
+				t.Errorf("robots.Allowed(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+			if r.crawlDelay != tt.wantCrawlDelay {
+				t.Errorf("robots.crawlDelay = %v, want %v", r.crawlDelay, tt.wantCrawlDelay)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_Robots(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\nCrawl-delay: 0.1\n"))
+			return
+		}
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	tests := []struct {
+		name        string
+		opts        []Option
+		path        string
+		wantErr     bool
+		wantElapsed time.Duration
+	}{
+		{
+			name:        "ok  crawl delay",
+			path:        "/public",
+			wantElapsed: 200 * time.Millisecond,
+		},
+		{
+			name:    "err  disallowed",
+			path:    "/private",
+			wantErr: true,
+		},
+		{
+			name: "ok  robots disabled",
+			opts: []Option{WithRobots(false)},
+			path: "/private",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(u, charmap.Windows1251, tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			start := time.Now()
+			for i := 0; i < 3; i++ {
+				_, err := f.Fetch(context.Background(), tt.path)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Fetcher.Fetch() error = %v, wantErr %v", err, tt.wantErr)
+				}
+
+				var derr *DisallowedError
+				if tt.wantErr && !errors.As(err, &derr) {
+					t.Fatalf("Fetcher.Fetch() error = %v, want %T", err, derr)
+				}
+			}
+
+			if elapsed := time.Since(start); elapsed < tt.wantElapsed {
+				t.Errorf("Fetcher.Fetch() elapsed = %v, want >= %v", elapsed, tt.wantElapsed)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_RobotsServerError(t *testing.T) {
+	var robotsRequests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			if atomic.AddInt32(&robotsRequests, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+			return
+		}
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	clock := &fakeClock{now: time.Now()}
+	f, err := New(u, charmap.Windows1251, WithClock(clock))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	steps := []struct {
+		advance     time.Duration
+		wantErr     bool
+		wantFetches int32
+	}{
+		{wantErr: true, wantFetches: 1},
+		{advance: robotsRetryTTL / 2, wantErr: true, wantFetches: 1},
+		{advance: robotsRetryTTL, wantFetches: 2},
+	}
+	for i, step := range steps {
+		clock.mu.Lock()
+		clock.now = clock.now.Add(step.advance)
+		clock.mu.Unlock()
+
+		_, err := f.Fetch(context.Background(), "/public")
+		var serr *StatusError
+		if step.wantErr && (!errors.As(err, &serr) || serr.StatusCode != http.StatusServiceUnavailable) {
+			t.Fatalf("step %d: Fetcher.Fetch() error = %v, want %T with status code %d", i, err, serr, http.StatusServiceUnavailable)
+		}
+		var derr *DisallowedError
+		if errors.As(err, &derr) {
+			t.Fatalf("step %d: Fetcher.Fetch() error = %v, want a temporary error", i, err)
+		}
+		if !step.wantErr && err != nil {
+			t.Fatalf("step %d: Fetcher.Fetch() error = %v", i, err)
+		}
+		if got := atomic.LoadInt32(&robotsRequests); got != step.wantFetches {
+			t.Errorf("step %d: robots.txt requests = %d, want %d", i, got, step.wantFetches)
+		}
+	}
+}
+
+func TestFetcher_Fetch_RobotsSlowOrigin(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			<-release
+		}
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer fast.Close()
+
+	u, _ := url.Parse(slow.URL)
+	f, err := New(u, charmap.Windows1251)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_, _ = f.Fetch(ctx, "/page")
+	}()
+
+	// the fetch from another origin is not blocked by the pending robots.txt of the slow one
+	done := make(chan error, 1)
+	go func() {
+		_, err := f.Fetch(context.Background(), fast.URL+"/page")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Fetcher.Fetch() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Fetcher.Fetch() is blocked by robots.txt of another origin")
+	}
+}
@@ -2,6 +2,8 @@ package fetcher
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	sdkerrors "github.com/linden-honey/linden-honey-sdk-go/errors"
 )
@@ -20,10 +22,56 @@ func (f Fetcher) Validate() error {
 		return sdkerrors.NewRequiredValueError("client")
 	}
 
+	if f.clock == nil {
+		return sdkerrors.NewRequiredValueError("clock")
+	}
+
+	if f.rnd == nil {
+		return sdkerrors.NewRequiredValueError("rnd")
+	}
+
+	if strings.TrimSpace(f.userAgent) == "" {
+		return sdkerrors.NewInvalidValueError("userAgent", sdkerrors.ErrEmptyValue)
+	}
+
 	if f.retry != nil {
 		if err := f.retry.Validate(); err != nil {
 			return sdkerrors.NewInvalidValueError("retry", err)
 		}
+
+		if f.retryable == nil {
+			return sdkerrors.NewRequiredValueError("retryable")
+		}
+	}
+
+	if f.breaker != nil && f.retryable == nil {
+		return sdkerrors.NewRequiredValueError("retryable")
+	}
+
+	if f.rateLimit != nil {
+		if err := f.rateLimit.Validate(); err != nil {
+			return sdkerrors.NewInvalidValueError("rateLimit", err)
+		}
+	}
+
+	for name := range f.header {
+		if strings.TrimSpace(name) == "" {
+			return sdkerrors.NewInvalidValueError("header", errors.New("should not contain empty names"))
+		}
+	}
+
+	if f.maxBodySize < 0 {
+		return sdkerrors.NewInvalidValueError("maxBodySize", errors.New("should be greater than or equal to zero"))
+	}
+
+	for i, t := range f.contentTypes {
+		if strings.TrimSpace(t) == "" {
+			return sdkerrors.NewInvalidValueError(fmt.Sprintf("contentTypes[%d]", i), sdkerrors.ErrEmptyValue)
+		}
+	}
+
+	if f.cache != nil && f.cacheTTL <= 0 {
+		return sdkerrors.NewInvalidValueError("cacheTTL", sdkerrors.ErrNonPositiveNumber)
 	}
 
 	return nil
@@ -35,6 +83,12 @@ func (cfg RetryConfig) Validate() error {
 		return sdkerrors.NewInvalidValueError("Attempts", sdkerrors.ErrNonPositiveNumber)
 	}
 
+	switch cfg.Strategy {
+	case "", BackoffConstant, BackoffLinear, BackoffExponential, BackoffDecorrelatedJitter:
+	default:
+		return sdkerrors.NewInvalidValueError("Strategy", fmt.Errorf("unsupported backoff strategy %q", cfg.Strategy))
+	}
+
 	if cfg.MinInterval <= 0 {
 		return sdkerrors.NewInvalidValueError("MinInterval", sdkerrors.ErrNonPositiveNumber)
 	}
@@ -47,8 +101,74 @@ func (cfg RetryConfig) Validate() error {
 		return sdkerrors.NewInvalidValueError("MinInterval", errors.New("should be less than or equal to MaxInterval"))
 	}
 
-	if cfg.Factor <= 0 {
-		return sdkerrors.NewInvalidValueError("Factor", sdkerrors.ErrNonPositiveNumber)
+	switch cfg.Strategy {
+	case "", BackoffLinear, BackoffExponential:
+		if cfg.Factor <= 0 {
+			return sdkerrors.NewInvalidValueError("Factor", sdkerrors.ErrNonPositiveNumber)
+		}
+	}
+
+	if cfg.MaxJitterInterval < 0 {
+		return sdkerrors.NewInvalidValueError("MaxJitterInterval", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.MaxJitterInterval > cfg.MaxInterval {
+		return sdkerrors.NewInvalidValueError("MaxJitterInterval", errors.New("should be less than or equal to MaxInterval"))
+	}
+
+	return nil
+}
+
+// Validate validates a [CircuitBreakerConfig] and returns an error if validation is failed.
+func (cfg CircuitBreakerConfig) Validate() error {
+	if cfg.Threshold <= 0 {
+		return sdkerrors.NewInvalidValueError("Threshold", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	if cfg.Cooldown <= 0 {
+		return sdkerrors.NewInvalidValueError("Cooldown", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	return nil
+}
+
+// Validate validates a [ClientConfig] and returns an error if validation is failed.
+func (cfg ClientConfig) Validate() error {
+	if cfg.Timeout < 0 {
+		return sdkerrors.NewInvalidValueError("Timeout", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.ProxyURL != nil && cfg.ProxyURL.Host == "" {
+		return sdkerrors.NewInvalidValueError("ProxyURL", errors.New("should be an absolute url"))
+	}
+
+	if cfg.MaxIdleConns < 0 {
+		return sdkerrors.NewInvalidValueError("MaxIdleConns", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.MaxIdleConnsPerHost < 0 {
+		return sdkerrors.NewInvalidValueError("MaxIdleConnsPerHost", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.MaxConnsPerHost < 0 {
+		return sdkerrors.NewInvalidValueError("MaxConnsPerHost", errors.New("should be greater than or equal to zero"))
+	}
+
+	if cfg.IdleConnTimeout < 0 {
+		return sdkerrors.NewInvalidValueError("IdleConnTimeout", errors.New("should be greater than or equal to zero"))
+	}
+
+	return nil
+}
+
+// Validate validates a [RateLimitConfig] and returns an error if validation is failed.
+func (cfg RateLimitConfig) Validate() error {
+	if cfg.RPS <= 0 {
+		return sdkerrors.NewInvalidValueError("RPS", sdkerrors.ErrNonPositiveNumber)
+	}
+
+	if cfg.Burst <= 0 {
+		return sdkerrors.NewInvalidValueError("Burst", sdkerrors.ErrNonPositiveNumber)
 	}
 
 	return nil
@@ -6,15 +6,26 @@ import (
 	"testing"
 	"time"
 
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 )
 
 func TestFetcher_Validate(t *testing.T) {
 	type fields struct {
-		baseURL  *url.URL
-		encoding *charmap.Charmap
-		client   httpClient
-		retry    *RetryConfig
+		baseURL   *url.URL
+		encoding  encoding.Encoding
+		client    httpClient
+		clock     Clock
+		rnd       Rand
+		userAgent string
+		retry     *RetryConfig
+		retryable RetryPolicy
+		cache     Cache
+		cacheTTL  time.Duration
+		rateLimit *RateLimitConfig
+
+		maxBodySize  int64
+		contentTypes []string
 	}
 	tests := []struct {
 		name    string
@@ -24,69 +35,197 @@ func TestFetcher_Validate(t *testing.T) {
 		{
 			name: "ok",
 			fields: fields{
-				baseURL:  &url.URL{},
-				encoding: charmap.Windows1251,
-				client:   &http.Client{},
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
 				retry: &RetryConfig{
 					Attempts:    3,
 					MinInterval: 1 * time.Second,
 					MaxInterval: 6 * time.Second,
 					Factor:      3 * time.Second,
 				},
+				retryable: DefaultRetryPolicy,
 			},
 		},
 		{
 			name: "err  no base url",
 			fields: fields{
-				baseURL:  nil,
-				encoding: charmap.Windows1251,
-				client:   &http.Client{},
+				baseURL:   nil,
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
 				retry: &RetryConfig{
 					Attempts:    3,
 					MinInterval: 1 * time.Second,
 					MaxInterval: 6 * time.Second,
 					Factor:      3 * time.Second,
 				},
+				retryable: DefaultRetryPolicy,
 			},
 			wantErr: true,
 		},
 		{
 			name: "err  no encoding",
 			fields: fields{
-				baseURL:  &url.URL{},
-				encoding: nil,
-				client:   &http.Client{},
+				baseURL:   &url.URL{},
+				encoding:  nil,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
 				retry: &RetryConfig{
 					Attempts:    3,
 					MinInterval: 1 * time.Second,
 					MaxInterval: 6 * time.Second,
 					Factor:      3 * time.Second,
 				},
+				retryable: DefaultRetryPolicy,
 			},
 			wantErr: true,
 		},
 		{
 			name: "err  no client",
 			fields: fields{
-				baseURL:  &url.URL{},
-				encoding: charmap.Windows1251,
-				client:   nil,
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    nil,
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
 				retry: &RetryConfig{
 					Attempts:    3,
 					MinInterval: 1 * time.Second,
 					MaxInterval: 6 * time.Second,
 					Factor:      3 * time.Second,
 				},
+				retryable: DefaultRetryPolicy,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  no clock",
+			fields: fields{
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     nil,
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  no rand",
+			fields: fields{
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       nil,
+				userAgent: DefaultUserAgent,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty user agent",
+			fields: fields{
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: "",
 			},
 			wantErr: true,
 		},
 		{
 			name: "err  invalid retry",
 			fields: fields{
-				baseURL:  &url.URL{},
-				encoding: charmap.Windows1251,
-				client:   &http.Client{},
-				retry:    &RetryConfig{},
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
+				retry:     &RetryConfig{},
+				retryable: DefaultRetryPolicy,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  no retry policy",
+			fields: fields{
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
+				retry: &RetryConfig{
+					Attempts:    3,
+					MinInterval: 1 * time.Second,
+					MaxInterval: 6 * time.Second,
+					Factor:      3 * time.Second,
+				},
+				retryable: nil,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid cache ttl",
+			fields: fields{
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
+				cache:     NewMemoryCache(1024),
+				cacheTTL:  0,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  invalid rate limit",
+			fields: fields{
+				baseURL:   &url.URL{},
+				encoding:  charmap.Windows1251,
+				client:    &http.Client{},
+				clock:     realClock{},
+				rnd:       newLockedRand(1),
+				userAgent: DefaultUserAgent,
+				rateLimit: &RateLimitConfig{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  negative max body size",
+			fields: fields{
+				baseURL:     &url.URL{},
+				encoding:    charmap.Windows1251,
+				client:      &http.Client{},
+				clock:       realClock{},
+				rnd:         newLockedRand(1),
+				userAgent:   DefaultUserAgent,
+				maxBodySize: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  empty content type",
+			fields: fields{
+				baseURL:      &url.URL{},
+				encoding:     charmap.Windows1251,
+				client:       &http.Client{},
+				clock:        realClock{},
+				rnd:          newLockedRand(1),
+				userAgent:    DefaultUserAgent,
+				contentTypes: []string{"text/html", " "},
 			},
 			wantErr: true,
 		},
@@ -94,10 +233,20 @@ func TestFetcher_Validate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			f := Fetcher{
-				baseURL:  tt.fields.baseURL,
-				encoding: tt.fields.encoding,
-				client:   tt.fields.client,
-				retry:    tt.fields.retry,
+				baseURL:   tt.fields.baseURL,
+				encoding:  tt.fields.encoding,
+				client:    tt.fields.client,
+				clock:     tt.fields.clock,
+				rnd:       tt.fields.rnd,
+				userAgent: tt.fields.userAgent,
+				retry:     tt.fields.retry,
+				retryable: tt.fields.retryable,
+				cache:     tt.fields.cache,
+				cacheTTL:  tt.fields.cacheTTL,
+				rateLimit: tt.fields.rateLimit,
+
+				maxBodySize:  tt.fields.maxBodySize,
+				contentTypes: tt.fields.contentTypes,
 			}
 			if err := f.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("Fetcher.Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -108,10 +257,12 @@ func TestFetcher_Validate(t *testing.T) {
 
 func TestRetryConfig_Validate(t *testing.T) {
 	type fields struct {
-		Attempts   int
-		MinTimeout time.Duration
-		MaxTimeout time.Duration
-		Factor     time.Duration
+		Attempts          int
+		Strategy          BackoffStrategy
+		MinTimeout        time.Duration
+		MaxTimeout        time.Duration
+		Factor            time.Duration
+		MaxJitterInterval time.Duration
 	}
 	tests := []struct {
 		name    string
@@ -167,6 +318,39 @@ func TestRetryConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ok  constant strategy without factor",
+			fields: fields{
+				Attempts:          3,
+				Strategy:          BackoffConstant,
+				MinTimeout:        1 * time.Second,
+				MaxTimeout:        6 * time.Second,
+				MaxJitterInterval: 1 * time.Second,
+			},
+		},
+		{
+			name: "err  unsupported strategy",
+			fields: fields{
+				Attempts:   3,
+				Strategy:   "random",
+				MinTimeout: 1 * time.Second,
+				MaxTimeout: 6 * time.Second,
+				Factor:     3 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  max jitter interval is greater than max interval",
+			fields: fields{
+				Attempts:          3,
+				Strategy:          BackoffLinear,
+				MinTimeout:        1 * time.Second,
+				MaxTimeout:        6 * time.Second,
+				Factor:            3 * time.Second,
+				MaxJitterInterval: 7 * time.Second,
+			},
+			wantErr: true,
+		},
 		{
 			name: "err  factor is non-positive number",
 			fields: fields{
@@ -181,10 +365,12 @@ func TestRetryConfig_Validate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := RetryConfig{
-				Attempts:    tt.fields.Attempts,
-				MinInterval: tt.fields.MinTimeout,
-				MaxInterval: tt.fields.MaxTimeout,
-				Factor:      tt.fields.Factor,
+				Attempts:          tt.fields.Attempts,
+				Strategy:          tt.fields.Strategy,
+				MinInterval:       tt.fields.MinTimeout,
+				MaxInterval:       tt.fields.MaxTimeout,
+				Factor:            tt.fields.Factor,
+				MaxJitterInterval: tt.fields.MaxJitterInterval,
 			}
 			if err := cfg.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("RetryConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -192,3 +378,171 @@ func TestRetryConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	type fields struct {
+		RPS   float64
+		Burst int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				RPS:   0.5,
+				Burst: 1,
+			},
+		},
+		{
+			name: "err  rps is non-positive number",
+			fields: fields{
+				RPS:   0,
+				Burst: 1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  burst is non-positive number",
+			fields: fields{
+				RPS:   1,
+				Burst: 0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := RateLimitConfig{
+				RPS:   tt.fields.RPS,
+				Burst: tt.fields.Burst,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RateLimitConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerConfig_Validate(t *testing.T) {
+	type fields struct {
+		Threshold int
+		Cooldown  time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Threshold: 3,
+				Cooldown:  time.Second,
+			},
+		},
+		{
+			name: "err  threshold is non-positive number",
+			fields: fields{
+				Threshold: 0,
+				Cooldown:  time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  cooldown is non-positive number",
+			fields: fields{
+				Threshold: 3,
+				Cooldown:  0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CircuitBreakerConfig{
+				Threshold: tt.fields.Threshold,
+				Cooldown:  tt.fields.Cooldown,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("CircuitBreakerConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientConfig_Validate(t *testing.T) {
+	type fields struct {
+		Timeout             time.Duration
+		ProxyURL            *url.URL
+		MaxIdleConns        int
+		MaxIdleConnsPerHost int
+		MaxConnsPerHost     int
+		IdleConnTimeout     time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				Timeout:             time.Second,
+				ProxyURL:            &url.URL{Scheme: "http", Host: "proxy:3128"},
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     time.Minute,
+			},
+		},
+		{
+			name:   "ok  zero values",
+			fields: fields{},
+		},
+		{
+			name: "err  negative timeout",
+			fields: fields{
+				Timeout: -time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  relative proxy url",
+			fields: fields{
+				ProxyURL: &url.URL{Path: "proxy"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  negative max idle conns",
+			fields: fields{
+				MaxIdleConns: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  negative max conns per host",
+			fields: fields{
+				MaxConnsPerHost: -1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ClientConfig{
+				Timeout:             tt.fields.Timeout,
+				ProxyURL:            tt.fields.ProxyURL,
+				MaxIdleConns:        tt.fields.MaxIdleConns,
+				MaxIdleConnsPerHost: tt.fields.MaxIdleConnsPerHost,
+				MaxConnsPerHost:     tt.fields.MaxConnsPerHost,
+				IdleConnTimeout:     tt.fields.IdleConnTimeout,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ClientConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package flight
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Group deduplicates concurrent calls with the same key, the zero value is ready to use.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+type call[T any] struct {
+	done   chan struct{}
+	val    T
+	err    error
+	refs   int
+	cancel context.CancelFunc
+}
+
+// Do calls the function once for concurrent calls with the same key and returns the shared result.
+//
+// The function is called with a context detached from the callers,
+// it's cancelled when all callers are gone, e.g. by cancellation of their contexts.
+// The last caller gone waits for the function to return, so no work is left behind.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+
+	c, ok := g.calls[key]
+	if !ok {
+		cctx, cancel := context.WithCancel(context.Background())
+		c = &call[T]{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = c
+
+		go g.run(cctx, key, c, fn)
+	}
+	c.refs++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.refs--
+		last := c.refs == 0
+		if last {
+			c.cancel()
+			g.forget(key, c) // hint: a new call is not joined to the cancelled one
+		}
+		g.mu.Unlock()
+
+		if last {
+			<-c.done
+		}
+
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Callers returns the number of callers waiting for the call with the key.
+func (g *Group[T]) Callers(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if c, ok := g.calls[key]; ok {
+		return c.refs
+	}
+
+	return 0
+}
+
+func (g *Group[T]) run(ctx context.Context, key string, c *call[T], fn func(ctx context.Context) (T, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("panic in a shared call: %v", r)
+		}
+
+		g.mu.Lock()
+		g.forget(key, c)
+		g.mu.Unlock()
+
+		c.cancel()
+		close(c.done)
+	}()
+
+	c.val, c.err = fn(ctx)
+}
+
+func (g *Group[T]) forget(key string, c *call[T]) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,142 @@
+package flight
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Do(t *testing.T) {
+	var (
+		g       Group[string]
+		calls   int32
+		release = make(chan struct{})
+	)
+	fn := func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "value", nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := g.Do(context.Background(), "key", fn)
+			if err != nil {
+				t.Errorf("Group.Do() error = %v", err)
+			}
+			results[i] = v
+		}(i)
+	}
+
+	for g.Callers("key") < n {
+		runtime.Gosched() // hint: let all callers join the call
+	}
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Group.Do() calls = %d, want %d", n, 1)
+	}
+	for _, v := range results {
+		if v != "value" {
+			t.Errorf("Group.Do() = %q, want %q", v, "value")
+		}
+	}
+}
+
+func TestGroup_Do_Cancel(t *testing.T) {
+	var g Group[string]
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return "", ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+
+	errc := make(chan error, 2)
+	go func() {
+		_, err := g.Do(ctx1, "key", fn)
+		errc <- err
+	}()
+	<-started
+	go func() {
+		_, err := g.Do(ctx2, "key", fn)
+		errc <- err
+	}()
+	for g.Callers("key") < 2 {
+		runtime.Gosched() // hint: let the second caller join the call
+	}
+
+	cancel1()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Group.Do() error = %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case <-cancelled:
+		t.Fatalf("Group.Do() shared call is cancelled while a caller is waiting")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel2()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Group.Do() error = %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("Group.Do() shared call is not cancelled when all callers are gone")
+	}
+}
+
+func TestGroup_Callers(t *testing.T) {
+	var g Group[string]
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = g.Do(context.Background(), "key", func(context.Context) (string, error) {
+			<-release
+			return "value", nil
+		})
+	}()
+
+	for g.Callers("key") < 1 {
+		runtime.Gosched()
+	}
+	if n := g.Callers("other"); n != 0 {
+		t.Errorf("Group.Callers() = %d, want %d", n, 0)
+	}
+
+	close(release)
+	<-done
+
+	if n := g.Callers("key"); n != 0 {
+		t.Errorf("Group.Callers() after return = %d, want %d", n, 0)
+	}
+}
+
+func TestGroup_Do_Panic(t *testing.T) {
+	var g Group[string]
+	_, err := g.Do(context.Background(), "key", func(context.Context) (string, error) {
+		panic("boom")
+	})
+	if err == nil {
+		t.Errorf("Group.Do() error = %v, want panic error", err)
+	}
+}
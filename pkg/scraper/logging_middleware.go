@@ -2,6 +2,7 @@ package scraper
 
 import (
 	"context"
+	"errors"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -45,7 +46,9 @@ func (mw *loggingMiddleware) GetSongs(ctx context.Context) (ss []song.Song, err
 	_ = level.Debug(mw.logger).Log("msg", "getting songs")
 
 	defer func() {
-		if err != nil {
+		if perr := new(PartialResultError); errors.As(err, &perr) {
+			_ = level.Warn(mw.logger).Log("msg", "partially got songs", "count", len(ss), "failures", len(perr.Failures), "failed_sources", len(perr.Sources))
+		} else if err != nil {
 			_ = level.Error(mw.logger).Log("msg", "failed to get songs", "err", err)
 		} else {
 			_ = level.Debug(mw.logger).Log("msg", "successfully got songs", "count", len(ss))
@@ -55,12 +58,36 @@ func (mw *loggingMiddleware) GetSongs(ctx context.Context) (ss []song.Song, err
 	return mw.next.GetSongs(ctx)
 }
 
+// StreamSongs wraps the [Service] call with logging attached.
+func (mw *loggingMiddleware) StreamSongs(ctx context.Context, fn func(s song.Song) error) (err error) {
+	_ = level.Debug(mw.logger).Log("msg", "streaming songs")
+
+	count := 0
+	defer func() {
+		if perr := new(PartialResultError); errors.As(err, &perr) {
+			_ = level.Warn(mw.logger).Log("msg", "partially streamed songs", "count", count, "failures", len(perr.Failures), "failed_sources", len(perr.Sources))
+		} else if err != nil {
+			_ = level.Error(mw.logger).Log("msg", "failed to stream songs", "count", count, "err", err)
+		} else {
+			_ = level.Debug(mw.logger).Log("msg", "successfully streamed songs", "count", count)
+		}
+	}()
+
+	return mw.next.StreamSongs(ctx, func(s song.Song) error {
+		count++
+
+		return fn(s)
+	})
+}
+
 // GetPreviews wraps the [Service] call with logging attached.
 func (mw *loggingMiddleware) GetPreviews(ctx context.Context) (pp []song.Metadata, err error) {
 	_ = level.Debug(mw.logger).Log("msg", "getting previews")
 
 	defer func() {
-		if err != nil {
+		if perr := new(PartialResultError); errors.As(err, &perr) {
+			_ = level.Warn(mw.logger).Log("msg", "partially got previews", "count", len(pp), "failed_sources", len(perr.Sources))
+		} else if err != nil {
 			_ = level.Error(mw.logger).Log("msg", "failed to get previews", "err", err)
 		} else {
 			_ = level.Debug(mw.logger).Log("msg", "successfully got previews", "count", len(pp))
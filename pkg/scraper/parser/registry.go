@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
+)
+
+// Constructor returns a new instance of the [scraper.Parser].
+type Constructor func() scraper.Parser
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Constructor)
+)
+
+func init() {
+	Register("grob", func() scraper.Parser {
+		return NewGrobParser()
+	})
+}
+
+// Register makes the parser constructor available by the name.
+//
+// It is intended to be called from the init function of the package implementing the parser,
+// it panics if the constructor is nil or the name is already registered.
+func Register(name string, c Constructor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if c == nil {
+		panic("parser: register constructor is nil")
+	}
+
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("parser: register called twice for parser %q", name))
+	}
+
+	registry[name] = c
+}
+
+// New returns a new instance of the [scraper.Parser] registered by the name or an error.
+func New(name string) (scraper.Parser, error) {
+	registryMu.RLock()
+	c, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown parser %q, registered parsers: %s", name, strings.Join(Names(), ", "))
+	}
+
+	return c(), nil
+}
+
+// Names returns a sorted list of the registered parser names.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  string
+		wantErr bool
+	}{
+		{
+			name:   "ok",
+			parser: "grob",
+		},
+		{
+			name:    "err  unknown parser",
+			parser:  "unknown",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.parser)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got == nil {
+				t.Errorf("New() = nil, want parser")
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	newTestParser := func() scraper.Parser {
+		return NewGrobParser()
+	}
+
+	Register("test", newTestParser)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+
+		delete(registry, "test")
+	})
+
+	if _, err := New("test"); err != nil {
+		t.Errorf("New() error = %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() did not panic on a duplicate name")
+		}
+	}()
+
+	Register("test", newTestParser)
+}
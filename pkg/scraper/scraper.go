@@ -2,19 +2,29 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+
+	"github.com/linden-honey/linden-honey-scraper-go/pkg/scraper/internal/flight"
 )
 
 // Scraper is an implementation of a song scraper from some source.
 type Scraper struct {
-	fetcher    Fetcher
-	parser     Parser
-	validation bool
+	fetcher     Fetcher
+	parser      Parser
+	validation  bool
+	partial     bool
+	concurrency int
+	flight      *flight.Group[*song.Song]
 }
 
+// DefaultConcurrency is the default maximum number of songs scraped concurrently by the [Scraper].
+const DefaultConcurrency = 8
+
 // Fetcher is a component for fetching content in an eager manner.
 type Fetcher interface {
 	Fetch(ctx context.Context, path string) (string, error)
@@ -33,8 +43,10 @@ func New(
 	opts ...Option,
 ) (*Scraper, error) {
 	scr := &Scraper{
-		fetcher: f,
-		parser:  p,
+		fetcher:     f,
+		parser:      p,
+		concurrency: DefaultConcurrency,
+		flight:      new(flight.Group[*song.Song]),
 	}
 
 	for _, opt := range opts {
@@ -58,23 +70,78 @@ func WithValidation(validation bool) Option {
 	}
 }
 
+// WithPartialResults enables or disables partial results mode for the [Scraper].
+//
+// In partial results mode [Scraper.GetSongs] does not stop on the first failure,
+// instead it returns all successfully scraped songs along with a [*PartialResultError].
+func WithPartialResults(partial bool) Option {
+	return func(scr *Scraper) {
+		scr.partial = partial
+	}
+}
+
+// WithConcurrency sets the maximum number of songs scraped concurrently by the [Scraper].
+func WithConcurrency(concurrency int) Option {
+	return func(scr *Scraper) {
+		scr.concurrency = concurrency
+	}
+}
+
 // GetSong scrapes a song by id and returns a pointer to the new instance of [song.Song] or an error.
+//
+// Concurrent calls with the same id are collapsed into a single scrape.
 func (scr *Scraper) GetSong(ctx context.Context, id string) (*song.Song, error) {
+	s, err := scr.flight.Do(ctx, id, func(ctx context.Context) (*song.Song, error) {
+		return scr.getSong(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return copySong(s), nil
+}
+
+// copySong returns a deep copy of the song, so a caller may modify the song shared by collapsed calls.
+func copySong(s *song.Song) *song.Song {
+	cp := *s
+	cp.Tags = append(song.Tags(nil), s.Tags...)
+	cp.Lyrics = make(song.Lyrics, 0, len(s.Lyrics))
+	for _, v := range s.Lyrics {
+		v.Quotes = append([]song.Quote(nil), v.Quotes...)
+		cp.Lyrics = append(cp.Lyrics, v)
+	}
+
+	return &cp
+}
+
+func (scr *Scraper) getSong(ctx context.Context, id string) (*song.Song, error) {
 	data, err := scr.fetcher.Fetch(ctx, fmt.Sprintf("text_print.php?area=go_texts&id=%s", id))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data: %w", err)
+		return nil, &SongError{
+			ID:    id,
+			Stage: StageFetch,
+			Err:   fmt.Errorf("failed to fetch data: %w", err),
+		}
 	}
 
 	s, err := scr.parser.ParseSong(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse a song: %w", err)
+		return nil, &SongError{
+			ID:    id,
+			Stage: StageParse,
+			Err:   fmt.Errorf("failed to parse a song: %w", err),
+		}
 	}
 
 	s.ID = id // backfill ID
 
 	if scr.validation {
 		if err := s.Validate(); err != nil {
-			return nil, fmt.Errorf("failed to validate a song: %w", err)
+			return nil, &SongError{
+				ID:    id,
+				Stage: StageValidate,
+				Err:   fmt.Errorf("failed to validate a song: %w", err),
+			}
 		}
 	}
 
@@ -82,45 +149,151 @@ func (scr *Scraper) GetSong(ctx context.Context, id string) (*song.Song, error)
 }
 
 // GetSongs scrapes all songs and returns a slice of [song.Song] instances or an error.
+//
+// In partial results mode the method returns successfully scraped songs
+// along with a [*PartialResultError], see [WithPartialResults].
 func (scr *Scraper) GetSongs(ctx context.Context) ([]song.Song, error) {
+	ss := make([]song.Song, 0)
+	err := scr.StreamSongs(ctx, func(s song.Song) error {
+		ss = append(ss, s)
+
+		return nil
+	})
+	if perr := new(PartialResultError); err != nil && !errors.As(err, &perr) {
+		return nil, err
+	}
+
+	sort.SliceStable(ss, func(i, j int) bool {
+		return ss[i].Title < ss[j].Title
+	})
+
+	return ss, err
+}
+
+// StreamSongs scrapes all songs and calls fn for each song as soon as it is scraped or returns an error.
+//
+// Songs are scraped by a bounded pool of workers, see [WithConcurrency],
+// fn is called sequentially in the order songs are scraped.
+// The first failure or an error returned by fn cancels all in-flight requests,
+// and the method returns only after every worker has exited.
+//
+// In partial results mode failures don't stop scraping, and the method returns
+// a [*PartialResultError] after all songs are scraped, see [WithPartialResults].
+func (scr *Scraper) StreamSongs(ctx context.Context, fn func(s song.Song) error) error {
 	ps, err := scr.GetPreviews(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get previews: %w", err)
+		return fmt.Errorf("failed to get previews: %w", err)
 	}
 
-	sc := make(chan song.Song, len(ps))
-	errc := make(chan error, 1)
-	for _, p := range ps {
-		go func(id string) {
-			s, err := scr.GetSong(ctx, id)
-			if err != nil {
-				errc <- fmt.Errorf("failed to get a song with id=%s: %w", id, err)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		ids  = make(chan string)
+		sc   = make(chan song.Song)
+		errc = make(chan error, 1)
+
+		mu       sync.Mutex
+		failures = make([]*SongError, 0)
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(ids)
+
+		for _, p := range ps {
+			select {
+			case ids <- p.ID:
+			case <-ctx.Done():
 				return
 			}
+		}
+	}()
 
-			sc <- *s
-		}(p.ID)
+	workers := scr.concurrency
+	if workers > len(ps) {
+		workers = len(ps)
 	}
 
-	ss := make([]song.Song, 0, len(ps))
-loop:
-	for {
-		select {
-		case s := <-sc:
-			ss = append(ss, s)
-			if len(ss) == len(ps) {
-				break loop
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for id := range ids {
+				s, err := scr.GetSong(ctx, id)
+				if err != nil && scr.partial {
+					serr := &SongError{ID: id, Err: err}
+					_ = errors.As(err, &serr)
+
+					mu.Lock()
+					failures = append(failures, serr)
+					mu.Unlock()
+
+					continue
+				}
+				if err != nil {
+					select {
+					case errc <- fmt.Errorf("failed to get a song with id=%s: %w", id, err):
+					default:
+					}
+					cancel()
+
+					return
+				}
+
+				select {
+				case sc <- *s:
+				case <-ctx.Done():
+					return
+				}
 			}
-		case err := <-errc:
-			return nil, err
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(sc)
+	}()
+
+	var fnErr error
+	for s := range sc {
+		if fnErr != nil {
+			continue // drain until all workers exit
+		}
+
+		if fnErr = fn(s); fnErr != nil {
+			cancel()
 		}
 	}
 
-	sort.SliceStable(ss, func(i, j int) bool {
-		return ss[i].Title < ss[j].Title
-	})
+	if fnErr != nil {
+		return fnErr
+	}
+
+	select {
+	case err := <-errc:
+		return err
+	default:
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to get songs: %w", err)
+	}
+
+	if len(failures) != 0 {
+		sort.SliceStable(failures, func(i, j int) bool {
+			return failures[i].ID < failures[j].ID
+		})
+
+		return &PartialResultError{
+			Failures: failures,
+		}
+	}
 
-	return ss, nil
+	return nil
 }
 
 // GetPreviews scrapes songs metadata and returns a slice of [song.Metadata] instances or an error.
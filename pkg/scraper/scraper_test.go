@@ -0,0 +1,410 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+)
+
+// fakeFetcher serves "texts" as a comma-separated list of ids and any other path as the song id.
+type fakeFetcher struct {
+	ids     []string
+	failIDs map[string]bool
+	delay   time.Duration
+	// release blocks song fetches until it's closed if set.
+	release chan struct{}
+	// entered receives a value when a song fetch is started if set.
+	entered chan struct{}
+	// returned is set by the test when the scraper returned, a song fetch is not expected after it.
+	returned     int32
+	inFlight     int32
+	maxSeen      int32
+	started      int32
+	startedLater int32
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, path string) (string, error) {
+	if path == "texts" {
+		return strings.Join(f.ids, ","), nil
+	}
+
+	atomic.AddInt32(&f.started, 1)
+	if atomic.LoadInt32(&f.returned) == 1 {
+		atomic.AddInt32(&f.startedLater, 1)
+	}
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		m := atomic.LoadInt32(&f.maxSeen)
+		if n <= m || atomic.CompareAndSwapInt32(&f.maxSeen, m, n) {
+			break
+		}
+	}
+
+	if f.entered != nil {
+		f.entered <- struct{}{}
+	}
+
+	id := path[strings.LastIndex(path, "=")+1:]
+	if f.failIDs[id] {
+		return "", errors.New("boom")
+	}
+
+	var wait <-chan time.Time
+	if f.release == nil {
+		wait = time.After(f.delay)
+	}
+
+	select {
+	case <-f.release:
+		return id, nil
+	case <-wait:
+		return id, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+type fakeParser struct{}
+
+func (p fakeParser) ParseSong(input string) (*song.Song, error) {
+	return &song.Song{
+		Metadata: song.Metadata{
+			Title: "title " + input,
+		},
+	}, nil
+}
+
+func (p fakeParser) ParsePreviews(input string) ([]song.Metadata, error) {
+	ps := make([]song.Metadata, 0)
+	for _, id := range strings.Split(input, ",") {
+		ps = append(ps, song.Metadata{
+			ID:    id,
+			Title: "title " + id,
+		})
+	}
+
+	return ps, nil
+}
+
+func makeIDs(n int) []string {
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		ids = append(ids, fmt.Sprintf("%03d", i))
+	}
+
+	return ids
+}
+
+func TestScraper_GetSongs(t *testing.T) {
+	type fields struct {
+		fetcher     *fakeFetcher
+		partial     bool
+		concurrency int
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		wantCount    int
+		wantFailures []string
+		wantErr      bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				fetcher: &fakeFetcher{
+					ids:   makeIDs(50),
+					delay: time.Millisecond,
+				},
+				concurrency: 4,
+			},
+			wantCount: 50,
+		},
+		{
+			name: "ok  concurrency is greater than songs count",
+			fields: fields{
+				fetcher: &fakeFetcher{
+					ids: makeIDs(3),
+				},
+				concurrency: 10,
+			},
+			wantCount: 3,
+		},
+		{
+			name: "ok  partial results",
+			fields: fields{
+				fetcher: &fakeFetcher{
+					ids:     makeIDs(20),
+					failIDs: map[string]bool{"015": true, "002": true},
+				},
+				partial:     true,
+				concurrency: 4,
+			},
+			wantCount:    18,
+			wantFailures: []string{"002", "015"},
+			wantErr:      true,
+		},
+		{
+			name: "err  failed to get a song",
+			fields: fields{
+				fetcher: &fakeFetcher{
+					ids:     makeIDs(50),
+					failIDs: map[string]bool{"002": true},
+					delay:   time.Second,
+				},
+				concurrency: 4,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Cleanup(func() {
+			// hint: fetches left behind by a case are detected after the other cases are done
+			if n := atomic.LoadInt32(&tt.fields.fetcher.startedLater); n != 0 {
+				t.Errorf("%s: Scraper.GetSongs() fetches started after return = %d, want 0", tt.name, n)
+			}
+		})
+		t.Run(tt.name, func(t *testing.T) {
+			scr, err := New(
+				tt.fields.fetcher,
+				fakeParser{},
+				WithPartialResults(tt.fields.partial),
+				WithConcurrency(tt.fields.concurrency),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := scr.GetSongs(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scraper.GetSongs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("Scraper.GetSongs() count = %d, want %d", len(got), tt.wantCount)
+			}
+			if tt.wantFailures != nil {
+				var perr *PartialResultError
+				if !errors.As(err, &perr) {
+					t.Fatalf("Scraper.GetSongs() error = %v, want %T", err, perr)
+				}
+				gotFailures := make([]string, 0)
+				for _, f := range perr.Failures {
+					if f.Stage != StageFetch {
+						t.Errorf("Scraper.GetSongs() failure stage = %s, want %s", f.Stage, StageFetch)
+					}
+					gotFailures = append(gotFailures, f.ID)
+				}
+				if !reflect.DeepEqual(gotFailures, tt.wantFailures) {
+					t.Errorf("Scraper.GetSongs() failures = %v, want %v", gotFailures, tt.wantFailures)
+				}
+			}
+			if max := atomic.LoadInt32(&tt.fields.fetcher.maxSeen); int(max) > tt.fields.concurrency {
+				t.Errorf("Scraper.GetSongs() concurrency = %d, want <= %d", max, tt.fields.concurrency)
+			}
+			if n := atomic.LoadInt32(&tt.fields.fetcher.inFlight); n != 0 {
+				t.Errorf("Scraper.GetSongs() in-flight fetches after return = %d, want 0", n)
+			}
+
+			atomic.StoreInt32(&tt.fields.fetcher.returned, 1)
+		})
+	}
+}
+
+func TestScraper_GetSongs_Cancel(t *testing.T) {
+	f := &fakeFetcher{
+		ids:     makeIDs(20),
+		release: make(chan struct{}),
+		entered: make(chan struct{}),
+	}
+	scr, err := New(f, fakeParser{}, WithConcurrency(5))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := scr.GetSongs(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("Scraper.GetSongs() error = %v, want %v", err, context.Canceled)
+		}
+	}()
+
+	for i := 0; i < 5; i++ {
+		<-f.entered
+	}
+	cancel()
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&f.inFlight); n != 0 {
+		t.Errorf("Scraper.GetSongs() in-flight fetches after return = %d, want 0", n)
+	}
+}
+
+func TestScraper_StreamSongs(t *testing.T) {
+	errStop := errors.New("stop")
+	tests := []struct {
+		name      string
+		limit     int
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name:      "ok",
+			limit:     -1,
+			wantCount: 30,
+		},
+		{
+			name:      "err  callback failed",
+			limit:     3,
+			wantCount: 3,
+			wantErr:   errStop,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeFetcher{
+				ids:   makeIDs(30),
+				delay: time.Millisecond,
+			}
+			scr, err := New(f, fakeParser{}, WithConcurrency(4))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			count := 0
+			err = scr.StreamSongs(context.Background(), func(s song.Song) error {
+				if count == tt.limit {
+					return errStop
+				}
+				count++
+
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Scraper.StreamSongs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("Scraper.StreamSongs() count = %d, want %d", count, tt.wantCount)
+			}
+			if n := atomic.LoadInt32(&f.inFlight); n != 0 {
+				t.Errorf("Scraper.StreamSongs() in-flight fetches after return = %d, want 0", n)
+			}
+		})
+	}
+}
+
+// releaseAfterCallers releases the fetches of the song when n callers are waiting for it.
+func releaseAfterCallers(scr *Scraper, f *fakeFetcher, id string, n int) {
+	for scr.flight.Callers(id) < n {
+		runtime.Gosched()
+	}
+	close(f.release)
+}
+
+func TestScraper_GetSong_Dedup(t *testing.T) {
+	f := &fakeFetcher{
+		release: make(chan struct{}),
+	}
+	scr, err := New(f, fakeParser{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s, err := scr.GetSong(context.Background(), "001")
+			if err != nil {
+				t.Errorf("Scraper.GetSong() error = %v", err)
+				return
+			}
+			if s.ID != "001" {
+				t.Errorf("Scraper.GetSong() id = %v, want %v", s.ID, "001")
+			}
+		}()
+	}
+	releaseAfterCallers(scr, f, "001", 10)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&f.started); n != 1 {
+		t.Errorf("Scraper.GetSong() fetches = %d, want %d", n, 1)
+	}
+}
+
+// fakeLyricsParser parses a song with tags and lyrics.
+type fakeLyricsParser struct {
+	fakeParser
+}
+
+func (p fakeLyricsParser) ParseSong(input string) (*song.Song, error) {
+	return &song.Song{
+		Metadata: song.Metadata{
+			Title: "title " + input,
+			Tags:  song.Tags{{Name: "author", Value: "author " + input}},
+		},
+		Lyrics: song.Lyrics{
+			{Quotes: []song.Quote{{Phrase: "phrase " + input}}},
+		},
+	}, nil
+}
+
+func TestScraper_GetSong_Copy(t *testing.T) {
+	f := &fakeFetcher{
+		release: make(chan struct{}),
+	}
+	scr, err := New(f, fakeLyricsParser{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var (
+		wg  sync.WaitGroup
+		got [2]*song.Song
+	)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			s, err := scr.GetSong(context.Background(), "001")
+			if err != nil {
+				t.Errorf("Scraper.GetSong() error = %v", err)
+				return
+			}
+			got[i] = s
+		}(i)
+	}
+	releaseAfterCallers(scr, f, "001", len(got))
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&f.started); n != 1 {
+		t.Fatalf("Scraper.GetSong() fetches = %d, want %d", n, 1)
+	}
+	if got[0] == nil || got[1] == nil {
+		t.FailNow()
+	}
+
+	got[0].Tags[0].Value = "modified"
+	got[0].Lyrics[0].Quotes[0].Phrase = "modified"
+
+	want, _ := fakeLyricsParser{}.ParseSong("001")
+	want.ID = "001"
+	if !reflect.DeepEqual(got[1], want) {
+		t.Errorf("Scraper.GetSong() = %+v, want %+v", got[1], want)
+	}
+}
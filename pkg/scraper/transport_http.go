@@ -1,11 +1,19 @@
 package scraper
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
 	sdkhttp "github.com/linden-honey/linden-honey-sdk-go/transport/http"
 )
 
@@ -25,9 +33,9 @@ func makeGetSongHTTPHandlerFunc(svc Service) http.HandlerFunc {
 		id := chi.URLParam(r, "id")
 		s, err := svc.GetSong(r.Context(), id)
 		if err != nil {
-			_ = sdkhttp.EncodeJSONError(
+			_ = encodeJSONError(
 				w,
-				http.StatusNotFound,
+				songErrorStatus(err),
 				fmt.Errorf("failed to get song by id=%s: %w", id, err),
 			)
 
@@ -38,36 +46,339 @@ func makeGetSongHTTPHandlerFunc(svc Service) http.HandlerFunc {
 	}
 }
 
+const (
+	// FailuresCountHeader is the response header with the number of songs failed to be scraped.
+	FailuresCountHeader = "X-Failures-Count"
+	// FailedSourcesHeader is the response header with the comma-separated names of the failed sources
+	// tolerated by the aggregation policy.
+	FailedSourcesHeader = "X-Failed-Sources"
+	// StreamErrorTrailer is the response trailer with an error happened during streaming.
+	StreamErrorTrailer = "X-Stream-Error"
+	// NDJSONContentType is the content type of the newline delimited JSON.
+	NDJSONContentType = "application/x-ndjson"
+)
+
+type songsResponse struct {
+	Songs         []song.Song     `json:"songs"`
+	Failures      []songFailure   `json:"failures"`
+	FailedSources []sourceFailure `json:"failed_sources"`
+}
+
+type songFailure struct {
+	ID    string `json:"id"`
+	Stage Stage  `json:"stage"`
+	Error string `json:"error"`
+}
+
+type sourceFailure struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
 func makeGetSongsHTTPHandlerFunc(svc Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if prefersMediaType(r, NDJSONContentType, "application/json") {
+			streamSongsNDJSON(w, r, svc)
+
+			return
+		}
+
 		ss, err := svc.GetSongs(r.Context())
-		if err != nil {
-			_ = sdkhttp.EncodeJSONError(
+		var perr *PartialResultError
+		if err != nil && !errors.As(err, &perr) {
+			_ = encodeJSONError(
 				w,
-				http.StatusUnprocessableEntity,
+				errorStatus(err),
 				fmt.Errorf("failed to get songs: %w", err),
 			)
 
 			return
 		}
 
+		failures := make([]songFailure, 0)
+		failedSources := make([]sourceFailure, 0)
+		if perr != nil {
+			for _, f := range perr.Failures {
+				failures = append(failures, songFailure{
+					ID:    f.ID,
+					Stage: f.Stage,
+					Error: f.Error(),
+				})
+			}
+
+			for _, src := range perr.Sources {
+				failedSources = append(failedSources, sourceFailure{
+					Source: src.Source,
+					Error:  src.Err.Error(),
+				})
+			}
+		}
+
+		w.Header().Set(FailuresCountHeader, strconv.Itoa(len(failures)))
+		writeFailedSources(w, perr)
+
+		if withFailures, _ := strconv.ParseBool(r.URL.Query().Get("failures")); withFailures {
+			_ = sdkhttp.EncodeJSONResponse(w, http.StatusOK, songsResponse{
+				Songs:         ss,
+				Failures:      failures,
+				FailedSources: failedSources,
+			})
+
+			return
+		}
+
 		_ = sdkhttp.EncodeJSONResponse(w, http.StatusOK, ss)
 	}
 }
 
+// streamSongsNDJSON writes songs one per line and flushes each one as soon as it is scraped.
+// Headers are sent with the first song, so the failures count, the failed sources and a streaming error
+// are reported in the response trailers.
+func streamSongsNDJSON(w http.ResponseWriter, r *http.Request, svc Service) {
+	w.Header().Set("Trailer", strings.Join([]string{FailuresCountHeader, FailedSourcesHeader, StreamErrorTrailer}, ", "))
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	started := false
+	start := func() {
+		if !started {
+			w.Header().Set("Content-Type", NDJSONContentType)
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+	}
+
+	err := svc.StreamSongs(r.Context(), func(s song.Song) error {
+		start()
+		if err := enc.Encode(s); err != nil {
+			return fmt.Errorf("failed to encode a song: %w", err)
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+
+	var perr *PartialResultError
+	if err != nil && !errors.As(err, &perr) {
+		if !started {
+			w.Header().Del("Trailer")
+			_ = encodeJSONError(
+				w,
+				errorStatus(err),
+				fmt.Errorf("failed to stream songs: %w", err),
+			)
+
+			return
+		}
+
+		w.Header().Set(StreamErrorTrailer, fmt.Sprintf("failed to stream songs: %s", err))
+
+		return
+	}
+
+	start()
+
+	failures := 0
+	if perr != nil {
+		failures = len(perr.Failures)
+	}
+
+	w.Header().Set(FailuresCountHeader, strconv.Itoa(failures))
+	writeFailedSources(w, perr)
+}
+
+// writeFailedSources sets the failed sources header if any, see [FailedSourcesHeader].
+func writeFailedSources(w http.ResponseWriter, perr *PartialResultError) {
+	if perr == nil || len(perr.Sources) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(perr.Sources))
+	for _, src := range perr.Sources {
+		names = append(names, src.Source)
+	}
+
+	w.Header().Set(FailedSourcesHeader, strings.Join(names, ","))
+}
+
+// errorStatus returns the status of the response to a failed request:
+// 404 Not Found if the song is not found, 504 Gateway Timeout if a source timed out
+// and 502 Bad Gateway if the sources failed otherwise, e.g. the aggregation policy is not satisfied.
+func errorStatus(err error) int {
+	switch {
+	case isNotFound(err):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// songErrorStatus returns the status of the response to a failed request of a song by id,
+// see [errorStatus]. A page of the song failed to be parsed or validated is considered as missing.
+func songErrorStatus(err error) int {
+	missing := allCauses(err, func(err error) bool {
+		var serr *SongError
+		if errors.As(err, &serr) && (serr.Stage == StageParse || serr.Stage == StageValidate) {
+			return true
+		}
+
+		return isNotFound(err)
+	})
+	if missing {
+		return http.StatusNotFound
+	}
+
+	return errorStatus(err)
+}
+
+// isNotFound reports whether the error is caused by a missing song,
+// an error with multiple causes is caused by a missing song if all the causes are.
+func isNotFound(err error) bool {
+	return allCauses(err, func(err error) bool {
+		if errors.Is(err, ErrNotFound) {
+			return true
+		}
+
+		var nferr interface{ NotFound() bool }
+
+		return errors.As(err, &nferr) && nferr.NotFound()
+	})
+}
+
+// allCauses reports whether the error matches,
+// an error with multiple causes matches if all the causes do.
+func allCauses(err error, match func(err error) bool) bool {
+	var merr interface{ Unwrap() []error }
+	if errors.As(err, &merr) {
+		causes := merr.Unwrap()
+		for _, cause := range causes {
+			if !allCauses(cause, match) {
+				return false
+			}
+		}
+
+		return len(causes) > 0
+	}
+
+	return match(err)
+}
+
+// prefersMediaType reports whether the request prefers the media type over the fallback one.
+// The media type must be listed explicitly with a quality value not less than the quality of the fallback,
+// so the media type with the zero quality value is refused.
+func prefersMediaType(r *http.Request, mediaType, fallback string) bool {
+	ranges := parseAccept(r)
+
+	q, ok := ranges[mediaType]
+	if !ok || q <= 0 {
+		return false
+	}
+
+	return q >= acceptQuality(ranges, fallback)
+}
+
+// acceptQuality returns the quality value of the media type by the most specific media range,
+// zero if the media type is not accepted.
+func acceptQuality(ranges map[string]float64, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	for _, mr := range []string{mediaType, typ + "/*", "*/*"} {
+		if q, ok := ranges[mr]; ok {
+			return q
+		}
+	}
+
+	return 0
+}
+
+// parseAccept returns the quality values of the media ranges listed in the Accept header,
+// an invalid quality value is considered as zero.
+func parseAccept(r *http.Request) map[string]float64 {
+	ranges := make(map[string]float64)
+	for _, v := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(v, ",") {
+			mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					q = 0
+				}
+			}
+			ranges[mt] = q
+		}
+	}
+
+	return ranges
+}
+
 func makeGetPreviewsHTTPHandlerFunc(svc Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ps, err := svc.GetPreviews(r.Context())
-		if err != nil {
-			_ = sdkhttp.EncodeJSONError(
+		var perr *PartialResultError
+		if err != nil && !errors.As(err, &perr) {
+			_ = encodeJSONError(
 				w,
-				http.StatusInternalServerError,
+				errorStatus(err),
 				fmt.Errorf("failed to get previews: %w", err),
 			)
 
 			return
 		}
 
+		writeFailedSources(w, perr)
 		_ = sdkhttp.EncodeJSONResponse(w, http.StatusOK, ps)
 	}
 }
+
+type errorResponse struct {
+	Timestamp string       `json:"timestamp"`
+	Error     string       `json:"error"`
+	Message   string       `json:"message"`
+	Causes    []errorCause `json:"causes,omitempty"`
+}
+
+type errorCause struct {
+	Source string `json:"source,omitempty"`
+	Error  string `json:"error"`
+}
+
+// encodeJSONError writes an error response, the causes of an error with multiple causes
+// (e.g. an aggregation error) are written as a structured array.
+func encodeJSONError(w http.ResponseWriter, code int, err error) error {
+	var merr interface{ Unwrap() []error }
+	if !errors.As(err, &merr) {
+		return sdkhttp.EncodeJSONError(w, code, err)
+	}
+
+	causes := make([]errorCause, 0, len(merr.Unwrap()))
+	for _, cause := range merr.Unwrap() {
+		var serr *SourceError
+		if errors.As(cause, &serr) {
+			causes = append(causes, errorCause{
+				Source: serr.Source,
+				Error:  serr.Err.Error(),
+			})
+
+			continue
+		}
+
+		causes = append(causes, errorCause{
+			Error: cause.Error(),
+		})
+	}
+
+	return sdkhttp.EncodeJSONResponse(w, code, errorResponse{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Error:     http.StatusText(code),
+		Message:   err.Error(),
+		Causes:    causes,
+	})
+}
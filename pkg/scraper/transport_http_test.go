@@ -0,0 +1,591 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/linden-honey/linden-honey-api-go/pkg/song"
+	sdkhttp "github.com/linden-honey/linden-honey-sdk-go/transport/http"
+)
+
+type fakeService struct {
+	songs []song.Song
+	err   error
+}
+
+func (svc *fakeService) GetSong(_ context.Context, id string) (*song.Song, error) {
+	if svc.err != nil {
+		return nil, svc.err
+	}
+
+	for _, s := range svc.songs {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (svc *fakeService) GetSongs(_ context.Context) ([]song.Song, error) {
+	return svc.songs, svc.err
+}
+
+func (svc *fakeService) StreamSongs(_ context.Context, fn func(s song.Song) error) error {
+	for _, s := range svc.songs {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+
+	return svc.err
+}
+
+func (svc *fakeService) GetPreviews(_ context.Context) ([]song.Metadata, error) {
+	ps := make([]song.Metadata, 0)
+	for _, s := range svc.songs {
+		ps = append(ps, s.Metadata)
+	}
+
+	return ps, svc.err
+}
+
+func makeSongs(ids ...string) []song.Song {
+	ss := make([]song.Song, 0)
+	for _, id := range ids {
+		ss = append(ss, song.Song{
+			Metadata: song.Metadata{
+				ID:    id,
+				Title: "title " + id,
+			},
+		})
+	}
+
+	return ss
+}
+
+func TestNewHTTPHandler_GetSong(t *testing.T) {
+	tests := []struct {
+		name       string
+		svc        *fakeService
+		id         string
+		wantStatus int
+	}{
+		{
+			name:       "ok",
+			svc:        &fakeService{songs: makeSongs("1")},
+			id:         "1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "err  not found",
+			svc:        &fakeService{songs: makeSongs("1")},
+			id:         "2",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "err  page without a song",
+			svc: &fakeService{
+				err: &SongError{ID: "2", Stage: StageParse, Err: errors.New("no song")},
+			},
+			id:         "2",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "err  invalid song by all sources",
+			svc: &fakeService{
+				err: multiError{
+					&SourceError{Source: "a", Err: &SongError{ID: "2", Stage: StageValidate, Err: errors.New("no title")}},
+					&SourceError{Source: "b", Err: ErrNotFound},
+				},
+			},
+			id:         "2",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "err  page without a song and a source timed out",
+			svc: &fakeService{
+				err: multiError{
+					&SourceError{Source: "a", Err: &SongError{ID: "2", Stage: StageParse, Err: errors.New("no song")}},
+					&SourceError{Source: "b", Err: context.DeadlineExceeded},
+				},
+			},
+			id:         "2",
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name: "err  failed to fetch",
+			svc: &fakeService{
+				err: &SongError{ID: "2", Stage: StageFetch, Err: errors.New("boom")},
+			},
+			id:         "2",
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.id, nil)
+			rec := httptest.NewRecorder()
+
+			NewHTTPHandler(tt.svc).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("GET /%s status = %d, want %d", tt.id, rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNewHTTPHandler_GetSongs(t *testing.T) {
+	partialErr := &PartialResultError{
+		Failures: []*SongError{
+			{ID: "3", Stage: StageParse, Err: errors.New("boom")},
+		},
+		Sources: []*SourceError{
+			{Source: "mirror", Err: errors.New("boom")},
+		},
+	}
+	sourcesErr := &PartialResultError{
+		Sources: []*SourceError{
+			{Source: "mirror", Err: errors.New("boom")},
+		},
+	}
+	tests := []struct {
+		name          string
+		svc           *fakeService
+		target        string
+		accept        string
+		wantStatus    int
+		wantType      string
+		wantCount     int
+		wantFailures  string
+		wantSources   string
+		wantStreamErr bool
+	}{
+		{
+			name:         "ok  json",
+			svc:          &fakeService{songs: makeSongs("1", "2")},
+			target:       "/",
+			wantStatus:   http.StatusOK,
+			wantType:     "application/json",
+			wantCount:    2,
+			wantFailures: "0",
+		},
+		{
+			name:         "ok  json with failures",
+			svc:          &fakeService{songs: makeSongs("1", "2"), err: partialErr},
+			target:       "/?failures=true",
+			wantStatus:   http.StatusOK,
+			wantType:     "application/json",
+			wantCount:    2,
+			wantFailures: "1",
+			wantSources:  "mirror",
+		},
+		{
+			name:         "ok  json with failed sources",
+			svc:          &fakeService{songs: makeSongs("1", "2"), err: sourcesErr},
+			target:       "/",
+			wantStatus:   http.StatusOK,
+			wantType:     "application/json",
+			wantCount:    2,
+			wantFailures: "0",
+			wantSources:  "mirror",
+		},
+		{
+			name:       "err  json",
+			svc:        &fakeService{err: errors.New("boom")},
+			target:     "/",
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:         "ok  ndjson",
+			svc:          &fakeService{songs: makeSongs("1", "2", "3")},
+			target:       "/",
+			accept:       "application/json;q=0.5, application/x-ndjson",
+			wantStatus:   http.StatusOK,
+			wantType:     NDJSONContentType,
+			wantCount:    3,
+			wantFailures: "0",
+		},
+		{
+			name:         "ok  ndjson refused",
+			svc:          &fakeService{songs: makeSongs("1", "2")},
+			target:       "/",
+			accept:       "application/json, application/x-ndjson;q=0",
+			wantStatus:   http.StatusOK,
+			wantType:     "application/json",
+			wantCount:    2,
+			wantFailures: "0",
+		},
+		{
+			name:         "ok  json preferred over ndjson",
+			svc:          &fakeService{songs: makeSongs("1", "2")},
+			target:       "/",
+			accept:       "application/json, application/x-ndjson;q=0.1",
+			wantStatus:   http.StatusOK,
+			wantType:     "application/json",
+			wantCount:    2,
+			wantFailures: "0",
+		},
+		{
+			name:         "ok  ndjson preferred over any type",
+			svc:          &fakeService{songs: makeSongs("1", "2")},
+			target:       "/",
+			accept:       "*/*;q=0.8, application/x-ndjson",
+			wantStatus:   http.StatusOK,
+			wantType:     NDJSONContentType,
+			wantCount:    2,
+			wantFailures: "0",
+		},
+		{
+			name:         "ok  ndjson with failures",
+			svc:          &fakeService{songs: makeSongs("1", "2"), err: partialErr},
+			target:       "/",
+			accept:       NDJSONContentType,
+			wantStatus:   http.StatusOK,
+			wantType:     NDJSONContentType,
+			wantCount:    2,
+			wantFailures: "1",
+			wantSources:  "mirror",
+		},
+		{
+			name:         "ok  ndjson with failed sources",
+			svc:          &fakeService{songs: makeSongs("1", "2"), err: sourcesErr},
+			target:       "/",
+			accept:       NDJSONContentType,
+			wantStatus:   http.StatusOK,
+			wantType:     NDJSONContentType,
+			wantCount:    2,
+			wantFailures: "0",
+			wantSources:  "mirror",
+		},
+		{
+			name:          "err  ndjson after first song",
+			svc:           &fakeService{songs: makeSongs("1"), err: errors.New("boom")},
+			target:        "/",
+			accept:        NDJSONContentType,
+			wantStatus:    http.StatusOK,
+			wantType:      NDJSONContentType,
+			wantCount:     1,
+			wantStreamErr: true,
+		},
+		{
+			name:       "err  ndjson before first song",
+			svc:        &fakeService{err: errors.New("boom")},
+			target:     "/",
+			accept:     NDJSONContentType,
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			NewHTTPHandler(tt.svc).ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d", tt.target, res.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus >= http.StatusBadRequest {
+				return
+			}
+
+			if got, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); got != tt.wantType {
+				t.Errorf("GET %s content type = %s, want %s", tt.target, got, tt.wantType)
+			}
+
+			count := 0
+			switch {
+			case tt.wantType == NDJSONContentType:
+				sc := bufio.NewScanner(res.Body)
+				for sc.Scan() {
+					var s song.Song
+					if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+						t.Fatalf("failed to decode a line: %v", err)
+					}
+					count++
+				}
+			case tt.target == "/?failures=true":
+				var body songsResponse
+				if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode a body: %v", err)
+				}
+				count = len(body.Songs)
+				if len(body.Failures) != len(partialErr.Failures) {
+					t.Errorf("GET %s failures = %v, want %d", tt.target, body.Failures, len(partialErr.Failures))
+				}
+				if len(body.FailedSources) != len(partialErr.Sources) {
+					t.Errorf("GET %s failed sources = %v, want %d", tt.target, body.FailedSources, len(partialErr.Sources))
+				}
+			default:
+				var body []song.Song
+				if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode a body: %v", err)
+				}
+				count = len(body)
+			}
+			if count != tt.wantCount {
+				t.Errorf("GET %s count = %d, want %d", tt.target, count, tt.wantCount)
+			}
+
+			header := res.Header
+			if tt.wantType == NDJSONContentType {
+				header = res.Trailer
+			}
+			if got := header.Get(FailuresCountHeader); got != tt.wantFailures {
+				t.Errorf("GET %s %s = %q, want %q", tt.target, FailuresCountHeader, got, tt.wantFailures)
+			}
+			if got := header.Get(FailedSourcesHeader); got != tt.wantSources {
+				t.Errorf("GET %s %s = %q, want %q", tt.target, FailedSourcesHeader, got, tt.wantSources)
+			}
+			if got := header.Get(StreamErrorTrailer); (got != "") != tt.wantStreamErr {
+				t.Errorf("GET %s %s = %q, wantStreamErr %v", tt.target, StreamErrorTrailer, got, tt.wantStreamErr)
+			}
+		})
+	}
+}
+
+func TestNewHTTPHandler_GetPreviews(t *testing.T) {
+	tests := []struct {
+		name        string
+		svc         *fakeService
+		wantStatus  int
+		wantSources string
+	}{
+		{
+			name:       "ok",
+			svc:        &fakeService{songs: makeSongs("1", "2")},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "ok  with failed sources",
+			svc: &fakeService{songs: makeSongs("1"), err: &PartialResultError{
+				Sources: []*SourceError{
+					{Source: "a", Err: errors.New("boom")},
+					{Source: "b", Err: errors.New("boom")},
+				},
+			}},
+			wantStatus:  http.StatusOK,
+			wantSources: "a,b",
+		},
+		{
+			name:       "err",
+			svc:        &fakeService{err: errors.New("boom")},
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/previews", nil)
+			rec := httptest.NewRecorder()
+
+			NewHTTPHandler(tt.svc).ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("GET /previews status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if got := res.Header.Get(FailedSourcesHeader); got != tt.wantSources {
+				t.Errorf("GET /previews %s = %q, want %q", FailedSourcesHeader, got, tt.wantSources)
+			}
+		})
+	}
+}
+
+type multiError []error
+
+func (err multiError) Error() string {
+	return "multiple errors"
+}
+
+func (err multiError) Unwrap() []error {
+	return err
+}
+
+func (err multiError) Is(target error) bool {
+	for _, cause := range err {
+		if errors.Is(cause, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestNewHTTPHandler_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCauses []errorCause
+	}{
+		{
+			name: "ok",
+			err:  errors.New("boom"),
+		},
+		{
+			name: "ok  with causes",
+			err: multiError{
+				&SourceError{Source: "a", Err: errors.New("boom")},
+				errors.New("bang"),
+			},
+			wantCauses: []errorCause{
+				{Source: "a", Error: "boom"},
+				{Error: "bang"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/previews", nil)
+			rec := httptest.NewRecorder()
+
+			NewHTTPHandler(&fakeService{err: tt.err}).ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusBadGateway {
+				t.Fatalf("GET /previews status = %d, want %d", res.StatusCode, http.StatusBadGateway)
+			}
+
+			var got errorResponse
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+			if got.Message == "" {
+				t.Errorf("GET /previews message is empty")
+			}
+			if !reflect.DeepEqual(got.Causes, tt.wantCauses) {
+				t.Errorf("GET /previews causes = %v, want %v", got.Causes, tt.wantCauses)
+			}
+		})
+	}
+}
+
+type notFoundError struct{}
+
+func (err notFoundError) Error() string {
+	return "status code 404"
+}
+
+func (err notFoundError) NotFound() bool {
+	return true
+}
+
+func Test_errorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "ok  not found",
+			err:  fmt.Errorf("failed to get a song: %w", ErrNotFound),
+			want: http.StatusNotFound,
+		},
+		{
+			name: "ok  not found reported by a cause",
+			err:  &SongError{ID: "1", Stage: StageFetch, Err: notFoundError{}},
+			want: http.StatusNotFound,
+		},
+		{
+			name: "ok  not found by all sources",
+			err: multiError{
+				&SourceError{Source: "a", Err: ErrNotFound},
+				&SourceError{Source: "b", Err: notFoundError{}},
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name: "ok  timed out",
+			err:  &SourceError{Source: "a", Err: context.DeadlineExceeded},
+			want: http.StatusGatewayTimeout,
+		},
+		{
+			name: "ok  not found by some sources and timed out",
+			err: multiError{
+				&SourceError{Source: "a", Err: ErrNotFound},
+				&SourceError{Source: "b", Err: context.DeadlineExceeded},
+			},
+			want: http.StatusGatewayTimeout,
+		},
+		{
+			name: "ok  failed",
+			err: multiError{
+				&SourceError{Source: "a", Err: errors.New("boom")},
+			},
+			want: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorStatus(tt.err); got != tt.want {
+				t.Errorf("errorStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_encodeJSONError(t *testing.T) {
+	err := multiError{
+		&SourceError{Source: "a", Err: errors.New("boom")},
+	}
+
+	want := httptest.NewRecorder()
+	_ = sdkhttp.EncodeJSONError(want, http.StatusBadGateway, err)
+
+	got := httptest.NewRecorder()
+	_ = encodeJSONError(got, http.StatusBadGateway, err)
+
+	if got.Code != want.Code {
+		t.Errorf("encodeJSONError() status = %d, want %d", got.Code, want.Code)
+	}
+	if got.Header().Get("Content-Type") != want.Header().Get("Content-Type") {
+		t.Errorf("encodeJSONError() content type = %s, want %s", got.Header().Get("Content-Type"), want.Header().Get("Content-Type"))
+	}
+
+	var gotBody, wantBody map[string]interface{}
+	if err := json.Unmarshal(got.Body.Bytes(), &gotBody); err != nil {
+		t.Fatalf("failed to decode the response: %v", err)
+	}
+	if err := json.Unmarshal(want.Body.Bytes(), &wantBody); err != nil {
+		t.Fatalf("failed to decode the sdk response: %v", err)
+	}
+
+	for k, wantV := range wantBody {
+		gotV, ok := gotBody[k]
+		if !ok {
+			t.Errorf("encodeJSONError() field %s is missing", k)
+			continue
+		}
+		if reflect.TypeOf(gotV) != reflect.TypeOf(wantV) {
+			t.Errorf("encodeJSONError() field %s = %T, want %T", k, gotV, wantV)
+			continue
+		}
+		if k != "timestamp" && !reflect.DeepEqual(gotV, wantV) {
+			t.Errorf("encodeJSONError() field %s = %v, want %v", k, gotV, wantV)
+		}
+	}
+	for k := range gotBody {
+		if _, ok := wantBody[k]; !ok && k != "causes" {
+			t.Errorf("encodeJSONError() field %s is unexpected", k)
+		}
+	}
+}
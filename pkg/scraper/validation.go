@@ -14,5 +14,9 @@ func (scr Scraper) Validate() error {
 		return sdkerrors.NewRequiredValueError("parser")
 	}
 
+	if scr.concurrency <= 0 {
+		return sdkerrors.NewInvalidValueError("concurrency", sdkerrors.ErrNonPositiveNumber)
+	}
+
 	return nil
 }
@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"testing"
+)
+
+func TestScraper_Validate(t *testing.T) {
+	type fields struct {
+		fetcher     Fetcher
+		parser      Parser
+		concurrency int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			fields: fields{
+				fetcher:     &fakeFetcher{},
+				parser:      fakeParser{},
+				concurrency: DefaultConcurrency,
+			},
+		},
+		{
+			name: "err  no fetcher",
+			fields: fields{
+				fetcher:     nil,
+				parser:      fakeParser{},
+				concurrency: DefaultConcurrency,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  no parser",
+			fields: fields{
+				fetcher:     &fakeFetcher{},
+				parser:      nil,
+				concurrency: DefaultConcurrency,
+			},
+			wantErr: true,
+		},
+		{
+			name: "err  concurrency is non-positive number",
+			fields: fields{
+				fetcher:     &fakeFetcher{},
+				parser:      fakeParser{},
+				concurrency: 0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scr := Scraper{
+				fetcher:     tt.fields.fetcher,
+				parser:      tt.fields.parser,
+				concurrency: tt.fields.concurrency,
+			}
+			if err := scr.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Scraper.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}